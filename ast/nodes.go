@@ -2,56 +2,213 @@ package ast
 
 type ASTNode interface{}
 
+// Pos is a position in the source, taken from the token a node starts at.
+type Pos struct {
+	File   string // Name of the source file, if the parser was given one
+	Offset int    // Byte offset from the start of the file
+	Line   int
+	Col    int
+}
+
 type Program struct {
 	Statements []ASTNode
+	Span       Span
 }
 
 type ParayuStatement struct {
 	Expression ASTNode
+	Span       Span
 }
 
 type KelkStatement struct {
 	Identifier string // Variable to store input
+	Type       string // Type of value to read: "string", "int" or "float64"
+	Pos        Pos
+	Span       Span
 }
 
 type AssignmentStatement struct {
 	Identifier string
 	Expression ASTNode
+	Compound   bool // Written as x += v and the like; Expression is then x + v
+	Pos        Pos
+	Span       Span
 }
 
 type IfStatement struct {
 	Condition ASTNode
 	Body      []ASTNode
 	ElseBody  []ASTNode // Optional else block
+	ElseIf    bool      // ElseBody is a single IfStatement written as alle ith_sheriyano
+	Span      Span
 }
 
 type WhileStatement struct {
 	Condition ASTNode
 	Body      []ASTNode
+	Label     string // Name given to the loop for nirthu and thudaru, if any
+	Span      Span
 }
 
+// ForStatement loops over the integers from Start to End, or, when
+// Iterable is set, over the elements of a list or the keys of a map.
 type ForStatement struct {
 	Identifier string
 	Start      ASTNode // Start of range
 	End        ASTNode // End of range
+	Step       ASTNode // Amount added each round, nil for 1
+	Exclusive  bool    // End is left out of the range, as in (0..<n)
+	Iterable   ASTNode // List or map to loop over instead of a range
+	Body       []ASTNode
+	Label      string // Name given to the loop for nirthu and thudaru, if any
+	Pos        Pos
+	Span       Span
+}
+
+type FunctionDeclaration struct {
+	Name       string
+	Parameters []string
+	ParamPos   []Pos // Where each parameter is named
 	Body       []ASTNode
+	Pos        Pos
+
+	// Filled in by the type checker once the function has been called.
+	ParamTypes []string
+	ReturnType string // Empty if the function does not return a value
+	Span       Span
+}
+
+// IndexAssignmentStatement stores Value at an index of a list, xs[i] = v,
+// or under a key of a map, m[k] = v.
+type IndexAssignmentStatement struct {
+	Target   IndexExpression
+	Value    ASTNode
+	Compound bool // Written as xs[i] += v and the like; Value is then xs[i] + v
+	Span     Span
+}
+
+type ReturnStatement struct {
+	Expression ASTNode // nil for a bare thirich_kodukk
+	Pos        Pos
+	Span       Span
+}
+
+// BreakStatement is nirthu, which leaves the innermost loop, or the loop
+// named by Label.
+type BreakStatement struct {
+	Label string
+	Pos   Pos
+	Span  Span
+}
+
+// ContinueStatement is thudaru, which skips to the next round of the
+// innermost loop, or of the loop named by Label.
+type ContinueStatement struct {
+	Label string
+	Pos   Pos
+	Span  Span
+}
+
+// ExpressionStatement is an expression evaluated for its side effects,
+// such as a function call on a line of its own.
+type ExpressionStatement struct {
+	Expression ASTNode
+	Span       Span
+}
+
+type CallExpression struct {
+	Function  string
+	Arguments []ASTNode
+	Pos       Pos
+	Type      string // Result type, filled in by the type checker
+	Span      Span
 }
 
 type BinaryExpression struct {
 	Left     ASTNode
 	Operator string
 	Right    ASTNode
+	Pos      Pos    // Position of the operator
+	Type     string // Filled in by the type checker
+	Span     Span
+}
+
+type UnaryExpression struct {
+	Operator string
+	Operand  ASTNode
+	Pos      Pos    // Position of the operator
+	Type     string // Filled in by the type checker
+	Span     Span
+}
+
+// ListLiteral is a list written out element by element: [1, 2, 3].
+type ListLiteral struct {
+	Elements []ASTNode
+	Pos      Pos
+	Type     string // List type such as "[]int", filled in by the type checker
+	Span     Span
+}
+
+// MapLiteral is a map written out entry by entry: {"a": 1, "b": 2}.
+type MapLiteral struct {
+	Keys   []ASTNode
+	Values []ASTNode // Values[i] belongs to Keys[i]
+	Pos    Pos
+	Type   string // Map type such as "map[string]int", filled in by the type checker
+	Span   Span
+}
+
+// IndexExpression reads one element of a list, xs[i], or the value stored
+// under a key of a map, m[k].
+type IndexExpression struct {
+	Collection ASTNode
+	Index      ASTNode
+	Pos        Pos    // Position of the '['
+	Type       string // Element type, filled in by the type checker
+	Span       Span
 }
 
 type StringLiteral struct {
 	Value string
+	Text  string // Spelling in the source, quotes and escapes included
+	Pos   Pos
+	Span  Span
+}
+
+// InterpolatedString is a string with expressions in braces, "Count: {n}".
+// Parts alternates between the text around the braces and the expressions
+// inside them, so it starts and ends with text and the expressions are at
+// the odd indices. The text is a StringLiteral, possibly empty, whose Text
+// is its spelling between the quotes and braces.
+type InterpolatedString struct {
+	Parts []ASTNode
+	Pos   Pos
+	Span  Span
 }
 
 type Identifier struct {
 	Name string
 	Type string // Store the inferred type: "string" or "int" (or other types later)
+	Pos  Pos
+	Span Span
 }
 
 type IntegerLiteral struct {
 	Value int
+	Pos   Pos
+	Span  Span
+}
+
+type FloatLiteral struct {
+	Value float64
+	Text  string // Spelling in the source, kept so codegen can emit it unchanged
+	Pos   Pos
+	Span  Span
+}
+
+// BooleanLiteral is sheri (true) or thettu (false).
+type BooleanLiteral struct {
+	Value bool
+	Pos   Pos
+	Span  Span
 }
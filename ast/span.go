@@ -0,0 +1,70 @@
+package ast
+
+// Span is the stretch of source a node was parsed from. Every node has one:
+// Start is its first character and End is just past its last, so the
+// source text of a node is source[Start.Offset:End.Offset]. The Pos fields
+// some nodes carry in addition mark the token errors about them point at,
+// such as the operator of a BinaryExpression.
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+// SpanOf returns the span of any node, or the zero Span for a value that is
+// not one.
+func SpanOf(node ASTNode) Span {
+	switch n := node.(type) {
+	case Program:
+		return n.Span
+	case ParayuStatement:
+		return n.Span
+	case KelkStatement:
+		return n.Span
+	case AssignmentStatement:
+		return n.Span
+	case IfStatement:
+		return n.Span
+	case WhileStatement:
+		return n.Span
+	case ForStatement:
+		return n.Span
+	case FunctionDeclaration:
+		return n.Span
+	case IndexAssignmentStatement:
+		return n.Span
+	case ReturnStatement:
+		return n.Span
+	case BreakStatement:
+		return n.Span
+	case ContinueStatement:
+		return n.Span
+	case ExpressionStatement:
+		return n.Span
+	case CallExpression:
+		return n.Span
+	case BinaryExpression:
+		return n.Span
+	case UnaryExpression:
+		return n.Span
+	case ListLiteral:
+		return n.Span
+	case MapLiteral:
+		return n.Span
+	case IndexExpression:
+		return n.Span
+	case StringLiteral:
+		return n.Span
+	case InterpolatedString:
+		return n.Span
+	case Identifier:
+		return n.Span
+	case IntegerLiteral:
+		return n.Span
+	case FloatLiteral:
+		return n.Span
+	case BooleanLiteral:
+		return n.Span
+	default:
+		return Span{}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/codegen"
+)
+
+// goMod is the go.mod of the temporary module a program is built in. The
+// generated code only imports the standard library.
+const goMod = "module malangprogram\n\ngo 1.22\n"
+
+// build implements "malang build": it compiles a malang program to Go in a
+// temporary module and runs go build there, leaving a standalone executable.
+// GOOS and GOARCH are passed through to go build, so setting them in the
+// environment cross-compiles.
+func build(args []string) {
+	flags := flag.NewFlagSet("build", flag.ExitOnError)
+	output := flags.String("o", "", "Write the executable to this file (default: the program's name)")
+	debugGoCode := flags.Bool("gocode", false, "Print generated Go code")
+	flags.Usage = func() {
+		fmt.Println("Usage: malang build [-o output] <filename.malang>")
+		fmt.Println("Set GOOS and GOARCH to build for another platform.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	// Allow flags after the file name too: malang build prog.malang -o prog
+	var positional []string
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		flags.Parse(flags.Args()[1:])
+	}
+	if len(positional) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	filename := positional[0]
+	source, program := parseFile(filename, false, false)
+	generatedCode, diags, err := codegen.GenerateCodeDiag(program)
+	if err != nil {
+		report(filename, source, diags)
+	}
+	if *debugGoCode {
+		fmt.Println("Generated Go Code:\n", generatedCode)
+	}
+
+	if *output == "" {
+		*output = defaultOutput(filename)
+	}
+	// go build runs in the temporary module, so a relative -o would land
+	// there instead of next to the caller.
+	outputPath, err := filepath.Abs(*output)
+	if err != nil {
+		fmt.Println("Error resolving output path:", err)
+		os.Exit(1)
+	}
+
+	dir, err := os.MkdirTemp("", "malang-build")
+	if err != nil {
+		fmt.Println("Error creating temporary module:", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{"go.mod": goMod, "main.go": generatedCode}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			fmt.Println("Error writing temporary module:", err)
+			os.Exit(1)
+		}
+	}
+
+	// The command inherits the environment, GOOS and GOARCH included.
+	cmd := exec.Command("go", "build", "-o", outputPath, ".")
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Println("Error building generated code:", err)
+		os.RemoveAll(dir) // os.Exit skips the deferred clean-up
+		os.Exit(1)
+	}
+}
+
+// defaultOutput names the executable for filename after the program, with
+// the .exe suffix Windows expects when that is the target.
+func defaultOutput(filename string) string {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	goos := os.Getenv("GOOS")
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
@@ -0,0 +1,312 @@
+package check
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
+)
+
+// Types are spelled the way Go spells them, since that is what the code
+// generator emits. The empty string is the unknown type: it is given to
+// expressions whose type cannot be worked out yet (a recursive call) or at
+// all (an undeclared variable), and never causes a second error.
+const (
+	Int    = "int"
+	Float  = "float64"
+	String = "string"
+	Bool   = "bool"
+)
+
+// scope is one level of the variable scope chain. The top level of the
+// program, the body of a function, every block and every loop variable have
+// a scope of their own. A variable lives in the scope where it is first
+// assigned and is visible in the scopes nested inside it.
+type scope struct {
+	vars    map[string]string
+	created map[string]ast.Pos // Where each variable was first assigned
+	parent  *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{vars: make(map[string]string), created: make(map[string]ast.Pos), parent: parent}
+}
+
+// define creates a variable in s.
+func (s *scope) define(name, t string, pos ast.Pos) {
+	s.vars[name] = t
+	s.created[name] = pos
+}
+
+func (s *scope) lookup(name string) (string, bool) {
+	for sc := s; sc != nil; sc = sc.parent {
+		if t, ok := sc.vars[name]; ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// Checker type checks malang programs. Top-level variables and functions
+// persist between calls to Check, so it can check a program that arrives a
+// piece at a time, as in the REPL.
+type Checker struct {
+	globals   *scope
+	functions map[string]*function
+	order     []string // Function names in declaration order
+
+	current    *function // Function whose body is being checked, nil at the top level
+	diags      []diag.Diagnostic
+	fnDiags    []diag.Diagnostic  // Errors found in function bodies
+	outOfScope map[string]ast.Pos // Variables whose block has ended, for hints
+	before     snapshot           // State before the last call to Check, for Undo
+}
+
+// snapshot is the state a call to Check can change: the top-level variables
+// and the functions, with the signatures inferred for them so far.
+type snapshot struct {
+	vars      map[string]string
+	created   map[string]ast.Pos
+	functions map[string]function
+	order     int
+}
+
+func (c *Checker) save() snapshot {
+	saved := snapshot{
+		vars:      make(map[string]string, len(c.globals.vars)),
+		created:   make(map[string]ast.Pos, len(c.globals.created)),
+		functions: make(map[string]function, len(c.functions)),
+		order:     len(c.order),
+	}
+	for name, t := range c.globals.vars {
+		saved.vars[name] = t
+	}
+	for name, pos := range c.globals.created {
+		saved.created[name] = pos
+	}
+	for name, fn := range c.functions {
+		saved.functions[name] = *fn
+	}
+	return saved
+}
+
+func (c *Checker) restore(saved snapshot) {
+	c.globals.vars, c.globals.created = saved.vars, saved.created
+	c.functions = make(map[string]*function, len(saved.functions))
+	for name, fn := range saved.functions {
+		fn := fn
+		c.functions[name] = &fn
+	}
+	c.order = c.order[:saved.order]
+}
+
+func New() *Checker {
+	return &Checker{globals: newScope(nil), functions: make(map[string]*function), outOfScope: make(map[string]ast.Pos)}
+}
+
+// Check type checks a complete program. It returns a copy of the program in
+// which every expression carries its type and every function declaration its
+// signature, ready for the code generator.
+func Check(program ast.Program) (ast.Program, []diag.Diagnostic, error) {
+	return New().check(program, true)
+}
+
+// Check type checks program in the context of everything checked before. If
+// there are errors, the top-level variables and functions it would have
+// introduced are discarded. Functions that have not been called yet are left unchecked,
+// because a later piece of the program may still call them.
+func (c *Checker) Check(program ast.Program) (ast.Program, []diag.Diagnostic, error) {
+	return c.check(program, false)
+}
+
+// Undo forgets the variables and functions introduced by the last call to
+// Check. The REPL calls it when the program it checked fails to run, since
+// then the variables it assigns may never have been given a value.
+func (c *Checker) Undo() {
+	c.restore(c.before)
+}
+
+// Functions returns the declarations of the functions that have been called,
+// and so carry their signature and checked body, in the order they were
+// declared. The REPL hands them to the interpreter, since a function is
+// often called in a later input than the one that declared it.
+func (c *Checker) Functions() []ast.FunctionDeclaration {
+	var decls []ast.FunctionDeclaration
+	for _, name := range c.order {
+		if fn := c.functions[name]; fn.state == resolved {
+			decls = append(decls, fn.decl)
+		}
+	}
+	return decls
+}
+
+// TypeOf returns the type of a top-level variable.
+func (c *Checker) TypeOf(name string) (string, bool) {
+	return c.globals.lookup(name)
+}
+
+func (c *Checker) check(program ast.Program, complete bool) (ast.Program, []diag.Diagnostic, error) {
+	c.diags, c.fnDiags = nil, nil
+	c.before = c.save()
+
+	seen := map[string]bool{}
+	for _, statement := range program.Statements {
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			if seen[decl.Name] {
+				c.errorf(decl.Pos, len(decl.Name), "", "function '%s' declared more than once", decl.Name)
+				continue
+			}
+			seen[decl.Name] = true
+			if _, builtin := builtins[decl.Name]; builtin {
+				c.errorf(decl.Pos, len(decl.Name), "pick another name", "'%s' is a builtin function", decl.Name)
+				continue
+			}
+			c.declareFunction(decl)
+		}
+	}
+
+	statements := make([]ast.ASTNode, len(program.Statements))
+	var emptyLists []int
+	for i, statement := range program.Statements {
+		declares := declaresVariable(statement, c.globals)
+		statements[i] = c.statement(statement, c.globals)
+		if declares && isEmptyList(statements[i]) {
+			emptyLists = append(emptyLists, i)
+		}
+	}
+	// A later piece of the program may still fill in an empty list.
+	c.settleEmptyLists(statements, emptyLists, c.globals, complete)
+	if complete {
+		c.resolveUncalled()
+	}
+
+	// Swap in the checked version of each function declaration.
+	for i, statement := range statements {
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			if fn := c.functions[decl.Name]; fn.state == resolved {
+				statements[i] = fn.decl
+			}
+		}
+	}
+
+	diags := append(c.diags, c.fnDiags...)
+	diag.Sort(diags)
+	err := diag.Err(diags)
+	if err != nil {
+		c.restore(c.before)
+	}
+	return ast.Program{Statements: statements, Span: program.Span}, diags, err
+}
+
+func (c *Checker) errorf(pos ast.Pos, span int, hint string, format string, args ...interface{}) {
+	d := diag.Errorf(pos.Line, pos.Col, span, format, args...)
+	d.Hint = hint
+	c.diags = append(c.diags, d)
+}
+
+// ExpressionType returns the type the checker annotated an expression with,
+// or "" if it is not an expression. Literals carry no annotation, since
+// their type never varies.
+func ExpressionType(expression ast.ASTNode) string {
+	switch e := expression.(type) {
+	case ast.StringLiteral, ast.InterpolatedString:
+		return String
+	case ast.IntegerLiteral:
+		return Int
+	case ast.FloatLiteral:
+		return Float
+	case ast.BooleanLiteral:
+		return Bool
+	case ast.Identifier:
+		return e.Type
+	case ast.CallExpression:
+		return e.Type
+	case ast.BinaryExpression:
+		return e.Type
+	case ast.UnaryExpression:
+		return e.Type
+	case ast.ListLiteral:
+		return e.Type
+	case ast.MapLiteral:
+		return e.Type
+	case ast.IndexExpression:
+		return e.Type
+	default:
+		return ""
+	}
+}
+
+// LoopVariableType returns the type of the variable of an oron_ayi over a
+// value of type collection: a list's element type or a map's key type.
+func LoopVariableType(collection string) string {
+	if isMap(collection) {
+		return keyType(collection)
+	}
+	return elementType(collection)
+}
+
+func isNumeric(t string) bool {
+	return t == Int || t == Float
+}
+
+// numericResult is the type of arithmetic on left and right: an int and a
+// float make a float, and only two ints make an int.
+func numericResult(left, right string) string {
+	if left == Float || right == Float {
+		return Float
+	}
+	return Int
+}
+
+// comparable reports whether values of the two types can be compared; an int
+// is promoted to a float to compare it with one.
+func comparable(left, right string) bool {
+	return (left == right && !isContainer(left)) || (isNumeric(left) && isNumeric(right))
+}
+
+// assignable reports whether a value of type value can be stored where a
+// target is expected. The only implicit conversion is int to float. An empty
+// list or map fits any list or map, and any list or map fits a variable that
+// only ever held an empty one.
+func assignable(target, value string) bool {
+	switch {
+	case target == value:
+		return true
+	case target == Float && value == Int:
+		return true
+	case isContainer(target) && isContainer(value):
+		return compatible(target, value)
+	default:
+		return false
+	}
+}
+
+// compatible reports whether two types can be used in place of each other,
+// treating an unknown element, key or value type as a wildcard.
+func compatible(a, b string) bool {
+	switch {
+	case a == b || a == "" || b == "":
+		return true
+	case isList(a) && isList(b):
+		return compatible(elementType(a), elementType(b))
+	case isMap(a) && isMap(b):
+		return compatible(keyType(a), keyType(b)) && compatible(valueType(a), valueType(b))
+	default:
+		return false
+	}
+}
+
+// unify returns the type that can hold values of both a and b, or false if
+// there is none.
+func unify(a, b string) (string, bool) {
+	switch {
+	case a == b:
+		return a, true
+	case isContainer(a) && isContainer(b) && assignable(a, b):
+		return maxType(a, b), true
+	case assignable(a, b):
+		return a, true
+	case assignable(b, a):
+		return b, true
+	default:
+		return "", false
+	}
+}
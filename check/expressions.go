@@ -0,0 +1,184 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// expr checks an expression and returns it annotated with its type.
+func (c *Checker) expr(expression ast.ASTNode, s *scope) (ast.ASTNode, string) {
+	switch e := expression.(type) {
+	case ast.StringLiteral:
+		return e, String
+	case ast.InterpolatedString:
+		// Like the operands of '+' next to a string, the expressions in
+		// braces may be of any type.
+		parts := make([]ast.ASTNode, len(e.Parts))
+		for i, part := range e.Parts {
+			parts[i], _ = c.expr(part, s)
+		}
+		e.Parts = parts
+		return e, String
+	case ast.IntegerLiteral:
+		return e, Int
+	case ast.FloatLiteral:
+		return e, Float
+	case ast.BooleanLiteral:
+		return e, Bool
+	case ast.Identifier:
+		t, ok := s.lookup(e.Name)
+		if !ok {
+			hint := "variables must be assigned before they are used"
+			if created, ended := c.outOfScope[e.Name]; ended {
+				hint = fmt.Sprintf("'%s' was created on line %d inside a block, and only exists until that block ends; assign it before the block to use it here", e.Name, created.Line)
+			}
+			c.errorf(e.Pos, len(e.Name), hint, "undeclared variable '%s'", e.Name)
+		}
+		e.Type = t
+		return e, t
+	case ast.CallExpression:
+		return c.call(e, s, true)
+	case ast.ListLiteral:
+		checked, t := c.listLiteral(e, s)
+		c.requireComplete(checked, t)
+		return checked, t
+	case ast.MapLiteral:
+		checked, t := c.mapLiteral(e, s)
+		c.requireComplete(checked, t)
+		return checked, t
+	case ast.IndexExpression:
+		e, t, _, _ := c.index(e, s)
+		return e, t
+	case ast.UnaryExpression:
+		var operandType string
+		e.Operand, operandType = c.expr(e.Operand, s)
+		e.Type = c.unary(e, operandType)
+		return e, e.Type
+	case ast.BinaryExpression:
+		var leftType, rightType string
+		e.Left, leftType = c.expr(e.Left, s)
+		e.Right, rightType = c.expr(e.Right, s)
+		e.Type = c.binary(e, leftType, rightType)
+		return e, e.Type
+	default:
+		return expression, ""
+	}
+}
+
+// stored checks an expression whose value is stored in a variable, a
+// parameter, an element or a return value. An empty list or map is allowed
+// there, since the place it is stored in gives it a type; everywhere else,
+// c.expr rejects one.
+func (c *Checker) stored(expression ast.ASTNode, s *scope) (ast.ASTNode, string) {
+	switch e := expression.(type) {
+	case ast.ListLiteral:
+		return c.listLiteral(e, s)
+	case ast.MapLiteral:
+		return c.mapLiteral(e, s)
+	default:
+		return c.expr(expression, s)
+	}
+}
+
+// requireComplete reports a list or map literal used where nothing can tell
+// what its elements would be, such as parayu([]) or neelam({}). Other
+// expressions are left alone: a variable or call of an unknown list type is
+// reported where the variable or function gets its type.
+func (c *Checker) requireComplete(literal ast.ASTNode, t string) {
+	switch literal.(type) {
+	case ast.ListLiteral, ast.MapLiteral:
+	default:
+		return
+	}
+	if !incomplete(t) {
+		return
+	}
+	hint := "put an element in it, or store it in a variable that gets one later"
+	if isMap(t) {
+		hint = "put an entry in it, or store it in a variable that gets one later"
+	}
+	pos, span := position(literal)
+	c.errorf(pos, span, hint, "cannot work out what kind of %s this is", kind(t))
+}
+
+// binary returns the type of a binary operation, reporting operand types the
+// operator does not accept.
+func (c *Checker) binary(e ast.BinaryExpression, left, right string) string {
+	known := left != "" && right != ""
+	mismatch := func(hint string) {
+		c.errorf(e.Pos, len(e.Operator), hint, "invalid operation: %s %s %s", describe(left), e.Operator, describe(right))
+	}
+
+	switch e.Operator {
+	case "+":
+		// A string on either side makes '+' concatenation.
+		if left == String || right == String {
+			return String
+		}
+		if known && !(isNumeric(left) && isNumeric(right)) {
+			mismatch("'+' adds numbers or joins strings")
+		}
+		return numericResult(left, right)
+	case "-", "*", "/", "**":
+		if known && !(isNumeric(left) && isNumeric(right)) {
+			mismatch(fmt.Sprintf("'%s' only works on numbers", e.Operator))
+		}
+		return numericResult(left, right)
+	case "%":
+		if known && (left != Int || right != Int) {
+			mismatch("'%' gives the remainder of dividing one whole number by another")
+		}
+		return Int
+	case "==", "!=":
+		if known && !comparable(left, right) {
+			if isContainer(left) || isContainer(right) {
+				mismatch("lists and maps cannot be compared; compare their elements instead")
+			} else {
+				mismatch("only values of the same type can be compared")
+			}
+		}
+		return Bool
+	case "<", ">", "<=", ">=":
+		if known && (!comparable(left, right) || left == Bool) {
+			mismatch("numbers can be ordered against numbers and strings against strings")
+		}
+		return Bool
+	case "&&", "||":
+		if known && (left != Bool || right != Bool) {
+			mismatch(fmt.Sprintf("'%s' combines conditions, such as x > 0 %s x < 10", e.Operator, e.Operator))
+		}
+		return Bool
+	default:
+		return ""
+	}
+}
+
+func (c *Checker) unary(e ast.UnaryExpression, operand string) string {
+	switch e.Operator {
+	case "!":
+		if operand != "" && operand != Bool {
+			c.errorf(e.Pos, len(e.Operator), "'!' negates a condition", "invalid operation: !%s", describe(operand))
+		}
+		return Bool
+	case "-":
+		if operand != "" && !isNumeric(operand) {
+			c.errorf(e.Pos, len(e.Operator), "'-' flips the sign of a number", "invalid operation: -%s", describe(operand))
+			return ""
+		}
+		return operand
+	default:
+		return ""
+	}
+}
+
+// position returns where an expression starts and how many columns it
+// covers, for pointing diagnostics at it. An expression that runs over
+// several lines is marked at its first character.
+func position(expression ast.ASTNode) (ast.Pos, int) {
+	span := ast.SpanOf(expression)
+	if span.End.Line != span.Start.Line {
+		return span.Start, 1
+	}
+	return span.Start, span.End.Col - span.Start.Col
+}
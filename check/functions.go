@@ -0,0 +1,183 @@
+package check
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+type resolveState int
+
+const (
+	unresolved resolveState = iota
+	resolving               // Return type is being inferred; calls inside are recursive
+	resolved
+)
+
+// function tracks the signature inferred for a malang function. Parameter
+// types come from the first call and the return type from the values the
+// function gives back.
+type function struct {
+	decl      ast.FunctionDeclaration
+	state     resolveState
+	hasResult bool
+}
+
+func (c *Checker) declareFunction(decl ast.FunctionDeclaration) {
+	if _, exists := c.functions[decl.Name]; !exists {
+		c.order = append(c.order, decl.Name)
+	}
+	c.functions[decl.Name] = &function{decl: decl}
+}
+
+// call checks a call expression. When valueNeeded is false the call is a
+// statement of its own and may call a function that returns nothing.
+func (c *Checker) call(call ast.CallExpression, s *scope, valueNeeded bool) (ast.ASTNode, string) {
+	arguments := make([]ast.ASTNode, len(call.Arguments))
+	argTypes := make([]string, len(call.Arguments))
+	for i, argument := range call.Arguments {
+		arguments[i], argTypes[i] = c.stored(argument, s)
+	}
+	call.Arguments = arguments
+
+	if _, builtin := builtins[call.Function]; builtin {
+		for i, argument := range call.Arguments {
+			c.requireComplete(argument, argTypes[i])
+		}
+		if !valueNeeded && call.Function == "cherkk" {
+			c.errorf(call.Pos, len(call.Function), "assign the result, as in: xs = cherkk(xs, 4)",
+				"the list returned by cherkk is not used")
+		}
+		if valueNeeded && call.Function == "kalay" {
+			c.errorf(call.Pos, len(call.Function), "", "function 'kalay' does not return a value")
+		}
+		return c.builtin(call, argTypes)
+	}
+
+	fn, ok := c.functions[call.Function]
+	if !ok {
+		c.errorf(call.Pos, len(call.Function), "declare it with pani", "undefined function '%s'", call.Function)
+		return call, ""
+	}
+	if _, shadowed := s.lookup(call.Function); shadowed {
+		// In the generated Go the variable hides the function, as a local
+		// hides anything declared outside it.
+		c.errorf(call.Pos, len(call.Function), "rename the variable or the function",
+			"cannot call function '%s' where a variable of the same name is in scope", call.Function)
+		return call, fn.decl.ReturnType
+	}
+	params := fn.decl.Parameters
+	if len(call.Arguments) != len(params) {
+		c.errorf(call.Pos, len(call.Function), "", "function '%s' takes %d arguments, got %d", call.Function, len(params), len(call.Arguments))
+		return call, ""
+	}
+
+	if fn.state == unresolved {
+		c.resolveFunction(fn, argTypes)
+		for i, paramType := range fn.decl.ParamTypes {
+			if incomplete(paramType) {
+				pos, span := position(call.Arguments[i])
+				c.errorf(pos, span, "the first call of a function fixes its parameter types; pass one with elements",
+					"cannot work out what kind of %s '%s' is", kind(paramType), params[i])
+			}
+		}
+	} else {
+		for i, argType := range argTypes {
+			if argType != "" && !assignable(fn.decl.ParamTypes[i], argType) {
+				pos, span := position(call.Arguments[i])
+				c.errorf(pos, span, "parameter types are fixed by the first call",
+					"function '%s' expects %s for '%s', got %s", call.Function, describe(fn.decl.ParamTypes[i]), params[i], describe(argType))
+			}
+		}
+	}
+
+	if valueNeeded && fn.state == resolved && !fn.hasResult {
+		c.errorf(call.Pos, len(call.Function), "", "function '%s' does not return a value", call.Function)
+	}
+	call.Type = fn.decl.ReturnType
+	return call, call.Type
+}
+
+// resolveFunction fixes the parameter types of fn and checks its body. The
+// body is checked twice: once to infer the return type, during which
+// recursive calls have the unknown type and errors are ignored, and once
+// more with the signature known to annotate it and report errors.
+func (c *Checker) resolveFunction(fn *function, paramTypes []string) {
+	fn.decl.ParamTypes = make([]string, len(paramTypes))
+	for i, paramType := range paramTypes {
+		if paramType == "" {
+			paramType = Int
+		}
+		fn.decl.ParamTypes[i] = paramType
+	}
+	frame := func() *scope {
+		params := newScope(nil)
+		for i, name := range fn.decl.Parameters {
+			params.define(name, fn.decl.ParamTypes[i], fn.decl.Pos)
+		}
+		return params
+	}
+
+	outerFunction, outerDiags := c.current, c.diags
+	c.current = fn
+
+	fn.state = resolving
+	c.block(fn.decl.Body, frame())
+	if fn.hasResult && fn.decl.ReturnType == "" {
+		fn.decl.ReturnType = Int
+	}
+	fn.state = resolved
+
+	c.diags = nil
+	fn.decl.Body = c.block(fn.decl.Body, frame())
+	if incomplete(fn.decl.ReturnType) {
+		c.errorf(fn.decl.Pos, len(fn.decl.Name), "return one with elements, or a variable that has some",
+			"cannot work out what kind of %s function '%s' returns", kind(fn.decl.ReturnType), fn.decl.Name)
+	}
+	c.fnDiags = append(c.fnDiags, c.diags...)
+
+	c.current, c.diags = outerFunction, outerDiags
+}
+
+func (c *Checker) returnStatement(st ast.ReturnStatement, s *scope) ast.ASTNode {
+	fn := c.current
+	if fn == nil {
+		return st // Rejected by the parser
+	}
+	if st.Expression == nil {
+		if fn.hasResult {
+			c.errorf(st.Pos, len("thirich_kodukk"), "", "missing return value in function '%s'", fn.decl.Name)
+		}
+		return st
+	}
+
+	var t string
+	st.Expression, t = c.stored(st.Expression, s)
+	fn.hasResult = true
+	switch {
+	case t == "":
+	case fn.decl.ReturnType == "":
+		fn.decl.ReturnType = t
+	case fn.state == resolving:
+		// Returning an int from one branch and a float from another makes
+		// the whole function return a float.
+		if unified, ok := unify(fn.decl.ReturnType, t); ok {
+			fn.decl.ReturnType = unified
+			break
+		}
+		fallthrough
+	case !assignable(fn.decl.ReturnType, t):
+		pos, span := position(st.Expression)
+		c.errorf(pos, span, "a function must always give back the same type",
+			"function '%s' returns %s here, but %s elsewhere", fn.decl.Name, describe(t), describe(fn.decl.ReturnType))
+	}
+	return st
+}
+
+// resolveUncalled checks the functions no call has reached, giving their
+// parameters the int type.
+func (c *Checker) resolveUncalled() {
+	for _, name := range c.order {
+		if fn := c.functions[name]; fn.state == unresolved {
+			c.resolveFunction(fn, make([]string, len(fn.decl.Parameters)))
+		}
+	}
+}
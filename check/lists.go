@@ -0,0 +1,292 @@
+package check
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// List types are spelled like Go slices: "[]int", "[][]string". The element
+// type of an empty list is not known until something is put in it, so [] has
+// the type "[]", whose element type is unknown.
+
+func isList(t string) bool {
+	return strings.HasPrefix(t, "[]")
+}
+
+func listOf(element string) string {
+	return "[]" + element
+}
+
+func elementType(list string) string {
+	return strings.TrimPrefix(list, "[]")
+}
+
+// incomplete reports whether t is a list or map whose element, key or value
+// type, at some level of nesting, is still unknown.
+func incomplete(t string) bool {
+	switch {
+	case isList(t):
+		return elementType(t) == "" || incomplete(elementType(t))
+	case isMap(t):
+		return keyType(t) == "" || valueType(t) == "" || incomplete(valueType(t))
+	default:
+		return false
+	}
+}
+
+// kind names the kind of container t is, for messages.
+func kind(t string) string {
+	if isMap(t) {
+		return "map"
+	}
+	return "list"
+}
+
+// isContainer reports whether t is a list or a map type.
+func isContainer(t string) bool {
+	return isList(t) || isMap(t)
+}
+
+// builtins are the functions every program can call without declaring them,
+// with the number of arguments each takes.
+var builtins = map[string]int{
+	"neelam": 1, // Length of a list or map
+	"cherkk": 2, // List with a value appended
+	"undo":   2, // Whether a map has a key
+	"kalay":  2, // Remove a key from a map
+}
+
+func (c *Checker) listLiteral(e ast.ListLiteral, s *scope) (ast.ASTNode, string) {
+	elements := make([]ast.ASTNode, len(e.Elements))
+	element := ""
+	for i, el := range e.Elements {
+		var t string
+		elements[i], t = c.stored(el, s)
+		switch {
+		case t == "":
+		case element == "":
+			element = t
+		default:
+			unified, ok := unify(element, t)
+			if !ok {
+				pos, span := position(elements[i])
+				c.errorf(pos, span, "every element of a list must have the same type",
+					"list element is %s, but earlier elements are %s", describe(t), describe(element))
+				continue
+			}
+			element = unified
+		}
+	}
+	e.Elements = elements
+	e.Type = listOf(element)
+	return e, e.Type
+}
+
+// index checks an index expression and returns it annotated, along with
+// its type and the types of the collection and index it is made of.
+func (c *Checker) index(e ast.IndexExpression, s *scope) (checked ast.IndexExpression, t, collection, index string) {
+	e.Collection, collection = c.expr(e.Collection, s)
+	e.Index, index = c.expr(e.Index, s)
+	if isMap(collection) {
+		e.Type = c.mapIndex(e, collection, index)
+		return e, e.Type, collection, index
+	}
+	if index != "" && index != Int {
+		pos, span := position(e.Index)
+		c.errorf(pos, span, "", "list index must be an int, got %s", describe(index))
+	}
+	if collection != "" && !isList(collection) {
+		c.errorf(e.Pos, 1, "", "cannot index %s", describe(collection))
+		return e, "", collection, index
+	}
+	e.Type = elementType(collection)
+	return e, e.Type, collection, index
+}
+
+func (c *Checker) indexAssignment(st ast.IndexAssignmentStatement, s *scope) ast.ASTNode {
+	var target, collection, key, value string
+	st.Target, target, collection, key = c.index(st.Target, s)
+	st.Value, value = c.stored(st.Value, s)
+
+	if isMap(collection) {
+		// Storing into an empty map variable fixes its key and value types.
+		if variable, ok := st.Target.Collection.(ast.Identifier); ok && incomplete(collection) {
+			c.assign(s, variable.Name, fillMap(collection, key, value), variable.Pos)
+			st.Target.Type = maxType(target, value)
+			return st
+		}
+		if target != "" && value != "" && !assignable(target, value) {
+			pos, span := position(st.Value)
+			c.errorf(pos, span, "every value of a map must have the same type",
+				"cannot store %s in a map of %s", describe(value), plural(target))
+		}
+		return st
+	}
+	if target != "" && value != "" && !assignable(target, value) {
+		pos, span := position(st.Value)
+		c.errorf(pos, span, "every element of a list must have the same type",
+			"cannot store %s in a list of %s", describe(value), plural(target))
+	}
+	return st
+}
+
+// builtin checks a call to one of the builtin functions.
+func (c *Checker) builtin(call ast.CallExpression, argTypes []string) (ast.ASTNode, string) {
+	if want := builtins[call.Function]; len(call.Arguments) != want {
+		c.errorf(call.Pos, len(call.Function), "", "function '%s' takes %d arguments, got %d", call.Function, want, len(call.Arguments))
+		return call, ""
+	}
+	list := argTypes[0]
+	var accepted bool
+	switch call.Function {
+	case "neelam":
+		accepted = isContainer(list)
+	case "cherkk":
+		accepted = isList(list)
+	default:
+		accepted = isMap(list)
+	}
+	if list != "" && !accepted {
+		needs := map[string]string{"neelam": "a list or a map", "cherkk": "a list", "undo": "a map", "kalay": "a map"}[call.Function]
+		pos, span := position(call.Arguments[0])
+		c.errorf(pos, span, "", "%s needs %s, got %s", call.Function, needs, describe(list))
+		return call, ""
+	}
+
+	switch call.Function {
+	case "neelam":
+		call.Type = Int
+	case "undo", "kalay":
+		m, key := list, argTypes[1]
+		if m != "" && key != "" && keyType(m) != "" && !assignable(keyType(m), key) {
+			pos, span := position(call.Arguments[1])
+			c.errorf(pos, span, "", "map key must be %s, got %s", describe(keyType(m)), describe(key))
+		}
+		if call.Function == "undo" {
+			call.Type = Bool
+		}
+	case "cherkk":
+		value := argTypes[1]
+		switch {
+		case list == "" || value == "":
+			call.Type = list
+		case elementType(list) == "" || assignable(elementType(list), value):
+			// Appending to an empty list fixes its element type.
+			call.Type = listOf(maxType(elementType(list), value))
+		default:
+			pos, span := position(call.Arguments[1])
+			c.errorf(pos, span, "every element of a list must have the same type",
+				"cannot append %s to a list of %s", describe(value), plural(elementType(list)))
+			call.Type = list
+		}
+	}
+	return call, call.Type
+}
+
+// maxType returns the more specific of two types that fit each other,
+// preferring a list whose element type is known over an empty one.
+func maxType(target, value string) string {
+	if target == "" || (incomplete(target) && !incomplete(value)) {
+		return value
+	}
+	return target
+}
+
+// settleEmptyLists gives the empty lists and maps assigned to new variables
+// in a block the type the variable ended up with, now that the whole block
+// has been checked. pending holds the indexes of those assignments.
+func (c *Checker) settleEmptyLists(statements []ast.ASTNode, pending []int, s *scope, report bool) {
+	for _, i := range pending {
+		st := statements[i].(ast.AssignmentStatement)
+		t := s.vars[st.Identifier]
+		if incomplete(t) {
+			if report {
+				if isMap(t) {
+					c.errorf(st.Pos, len(st.Identifier), "store a value in it, as in: m[key] = value",
+						"cannot work out what kind of map '%s' is", st.Identifier)
+				} else {
+					c.errorf(st.Pos, len(st.Identifier), "put a value in it with cherkk, or start the list with an element",
+						"cannot work out what kind of list '%s' is", st.Identifier)
+				}
+			}
+			continue
+		}
+		switch literal := st.Expression.(type) {
+		case ast.ListLiteral:
+			literal.Type = t
+			st.Expression = literal
+		case ast.MapLiteral:
+			literal.Type = t
+			st.Expression = literal
+		}
+		statements[i] = st
+	}
+}
+
+// declaresVariable reports whether statement assigns to a variable that
+// does not exist yet.
+func declaresVariable(statement ast.ASTNode, s *scope) bool {
+	st, ok := statement.(ast.AssignmentStatement)
+	if !ok {
+		return false
+	}
+	_, exists := s.lookup(st.Identifier)
+	return !exists
+}
+
+// isEmptyList reports whether statement is an assignment of a list or map
+// literal whose type is not fully known yet.
+func isEmptyList(statement ast.ASTNode) bool {
+	st, ok := statement.(ast.AssignmentStatement)
+	if !ok {
+		return false
+	}
+	switch literal := st.Expression.(type) {
+	case ast.ListLiteral:
+		return incomplete(literal.Type)
+	case ast.MapLiteral:
+		return incomplete(literal.Type)
+	default:
+		return false
+	}
+}
+
+func plural(t string) string {
+	if isList(t) {
+		return "lists of " + plural(elementType(t))
+	}
+	if isMap(t) {
+		return "maps from " + plural(keyType(t)) + " to " + plural(valueType(t))
+	}
+	switch t {
+	case Int:
+		return "ints"
+	case Float:
+		return "floats"
+	case String:
+		return "strings"
+	case Bool:
+		return "bools"
+	default:
+		return "values"
+	}
+}
+
+// Builtins returns the names of the builtin functions in alphabetical order.
+func Builtins() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Arity returns the number of arguments the builtin function name takes, or
+// false if there is no builtin of that name.
+func Arity(name string) (int, bool) {
+	arity, ok := builtins[name]
+	return arity, ok
+}
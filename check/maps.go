@@ -0,0 +1,98 @@
+package check
+
+import (
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// Map types are spelled like Go maps: "map[string]int". As with lists, an
+// empty map {} starts out with unknown key and value types, "map[]", which
+// the first entry stored in it fills in.
+
+func isMap(t string) bool {
+	return strings.HasPrefix(t, "map[")
+}
+
+func mapOf(key, value string) string {
+	return "map[" + key + "]" + value
+}
+
+// keyType and valueType split a map type. Keys are never containers, so the
+// first ']' ends the key type.
+func keyType(m string) string {
+	rest := strings.TrimPrefix(m, "map[")
+	return rest[:strings.Index(rest, "]")]
+}
+
+func valueType(m string) string {
+	rest := strings.TrimPrefix(m, "map[")
+	return rest[strings.Index(rest, "]")+1:]
+}
+
+// isKey reports whether values of type t can be map keys. Keys must have an
+// order so that loops over a map visit them the same way every time.
+func isKey(t string) bool {
+	return t == Int || t == Float || t == String
+}
+
+func (c *Checker) mapLiteral(e ast.MapLiteral, s *scope) (ast.ASTNode, string) {
+	keys := make([]ast.ASTNode, len(e.Keys))
+	values := make([]ast.ASTNode, len(e.Values))
+	key, value := "", ""
+	for i := range e.Keys {
+		var kt, vt string
+		keys[i], kt = c.expr(e.Keys[i], s)
+		values[i], vt = c.stored(e.Values[i], s)
+		if kt != "" && !isKey(kt) {
+			pos, span := position(keys[i])
+			c.errorf(pos, span, "", "map keys must be ints, floats or strings, got %s", describe(kt))
+		}
+		key = c.entryType(key, kt, keys[i], "key")
+		value = c.entryType(value, vt, values[i], "value")
+	}
+	e.Keys, e.Values = keys, values
+	e.Type = mapOf(key, value)
+	return e, e.Type
+}
+
+// entryType merges the type t of one key or value of a map literal into the
+// type so far, reporting a mismatch at expression.
+func (c *Checker) entryType(sofar, t string, expression ast.ASTNode, what string) string {
+	switch {
+	case t == "":
+		return sofar
+	case sofar == "":
+		return t
+	}
+	unified, ok := unify(sofar, t)
+	if !ok {
+		pos, span := position(expression)
+		c.errorf(pos, span, "every "+what+" of a map must have the same type",
+			"map %s is %s, but earlier %ss are %s", what, describe(t), what, describe(sofar))
+		return sofar
+	}
+	return unified
+}
+
+// mapIndex checks the key used to index a map of type m and returns the type
+// of the values stored under it.
+func (c *Checker) mapIndex(e ast.IndexExpression, m, key string) string {
+	if key != "" && !isKey(key) {
+		pos, span := position(e.Index)
+		c.errorf(pos, span, "", "map keys must be ints, floats or strings, got %s", describe(key))
+	} else if key != "" && keyType(m) != "" && !assignable(keyType(m), key) {
+		pos, span := position(e.Index)
+		c.errorf(pos, span, "", "map key must be %s, got %s", describe(keyType(m)), describe(key))
+	}
+	return valueType(m)
+}
+
+// fillMap returns the type of a map after storing a value of type value
+// under a key of type key, which completes a map that started out empty.
+func fillMap(m, key, value string) string {
+	if !incomplete(m) {
+		return m
+	}
+	return mapOf(maxType(keyType(m), key), maxType(valueType(m), value))
+}
@@ -0,0 +1,193 @@
+package check
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+func (c *Checker) block(statements []ast.ASTNode, s *scope) []ast.ASTNode {
+	if statements == nil {
+		return nil
+	}
+	checked := make([]ast.ASTNode, len(statements))
+	var emptyLists []int
+	for i, statement := range statements {
+		declares := declaresVariable(statement, s)
+		checked[i] = c.statement(statement, s)
+		if declares && isEmptyList(checked[i]) {
+			emptyLists = append(emptyLists, i)
+		}
+	}
+	c.settleEmptyLists(checked, emptyLists, s, true)
+	c.endScope(s)
+	return checked
+}
+
+func (c *Checker) statement(statement ast.ASTNode, s *scope) ast.ASTNode {
+	switch st := statement.(type) {
+	case ast.ParayuStatement:
+		st.Expression, _ = c.expr(st.Expression, s)
+		return st
+	case ast.KelkStatement:
+		if t, ok := s.lookup(st.Identifier); ok && t != "" && !assignable(t, st.Type) {
+			c.errorf(st.Pos, len(st.Identifier), kelkHint(t),
+				"cannot read %s into '%s', which holds %s", describe(st.Type), st.Identifier, describe(t))
+			return st
+		}
+		c.assign(s, st.Identifier, st.Type, st.Pos)
+		return st
+	case ast.AssignmentStatement:
+		var t string
+		st.Expression, t = c.stored(st.Expression, s)
+		if existing, ok := s.lookup(st.Identifier); ok && existing != "" && t != "" && !assignable(existing, t) {
+			c.errorf(st.Pos, len(st.Identifier), "a variable keeps the type of its first value; use a new name",
+				"cannot assign %s to '%s', which holds %s", describe(t), st.Identifier, describe(existing))
+			return st
+		}
+		c.assign(s, st.Identifier, t, st.Pos)
+		return st
+	case ast.IfStatement:
+		st.Condition = c.condition(st.Condition, s)
+		st.Body = c.block(st.Body, newScope(s))
+		st.ElseBody = c.block(st.ElseBody, newScope(s))
+		return st
+	case ast.WhileStatement:
+		st.Condition = c.condition(st.Condition, s)
+		st.Body = c.block(st.Body, newScope(s))
+		return st
+	case ast.ForStatement:
+		loopScope := newScope(s)
+		if st.Iterable != nil {
+			var t string
+			st.Iterable, t = c.expr(st.Iterable, s)
+			switch {
+			case isContainer(t):
+				loopScope.define(st.Identifier, LoopVariableType(t), st.Pos)
+			case t != "":
+				pos, span := position(st.Iterable)
+				c.errorf(pos, span, "loop over a range such as (1..5), a list or a map", "cannot loop over %s", describe(t))
+			}
+		} else {
+			st.Start = c.rangeBound(st.Start, s, "range bounds")
+			st.End = c.rangeBound(st.End, s, "range bounds")
+			if st.Step != nil {
+				st.Step = c.rangeBound(st.Step, s, "range steps")
+				if isZero(st.Step) {
+					pos, span := position(st.Step)
+					c.errorf(pos, span, "a step of 0 would never reach the end of the range", "the step of a range cannot be 0")
+				}
+			}
+			loopScope.define(st.Identifier, Int, st.Pos)
+		}
+		st.Body = c.block(st.Body, newScope(loopScope))
+		c.endScope(loopScope)
+		return st
+	case ast.IndexAssignmentStatement:
+		return c.indexAssignment(st, s)
+	case ast.FunctionDeclaration:
+		return st // Checked when first called
+	case ast.ReturnStatement:
+		return c.returnStatement(st, s)
+	case ast.ExpressionStatement:
+		if call, ok := st.Expression.(ast.CallExpression); ok {
+			st.Expression, _ = c.call(call, s, false)
+			return st
+		}
+		st.Expression, _ = c.expr(st.Expression, s)
+		return st
+	default:
+		return statement
+	}
+}
+
+// assign records that name holds a value of type t, updating the nearest
+// scope that already has the variable or else creating it in s.
+func (c *Checker) assign(s *scope, name string, t string, pos ast.Pos) {
+	for sc := s; sc != nil; sc = sc.parent {
+		if existing, ok := sc.vars[name]; ok {
+			if existing == "" || (incomplete(existing) && isContainer(t)) {
+				sc.vars[name] = maxType(existing, t)
+			}
+			return
+		}
+	}
+	s.define(name, t, pos)
+}
+
+// endScope remembers the variables of a scope that has ended, so that using
+// one afterwards can explain why it no longer exists.
+func (c *Checker) endScope(s *scope) {
+	for name, pos := range s.created {
+		c.outOfScope[name] = pos
+	}
+}
+
+func (c *Checker) condition(expression ast.ASTNode, s *scope) ast.ASTNode {
+	checked, t := c.expr(expression, s)
+	if t != "" && t != Bool {
+		pos, span := position(checked)
+		c.errorf(pos, span, "use a comparison such as x < 5", "condition must be a bool, got %s", describe(t))
+	}
+	return checked
+}
+
+// isZero reports whether expression is a 0 written out in the source.
+func isZero(expression ast.ASTNode) bool {
+	switch e := expression.(type) {
+	case ast.IntegerLiteral:
+		return e.Value == 0
+	case ast.UnaryExpression:
+		return e.Operator == "-" && isZero(e.Operand)
+	default:
+		return false
+	}
+}
+
+// rangeBound checks one of the numbers that make up a range, all of which
+// must be ints. what names them in errors.
+func (c *Checker) rangeBound(expression ast.ASTNode, s *scope, what string) ast.ASTNode {
+	checked, t := c.expr(expression, s)
+	if t != "" && t != Int {
+		pos, span := position(checked)
+		c.errorf(pos, span, "", "%s must be integers, got %s", what, describe(t))
+	}
+	return checked
+}
+
+func describe(t string) string {
+	switch t {
+	case Int:
+		return "an int"
+	case Float:
+		return "a float"
+	case String:
+		return "a string"
+	case Bool:
+		return "a bool"
+	}
+	switch {
+	case t == "[]":
+		return "an empty list"
+	case isList(t):
+		return "a list of " + plural(elementType(t))
+	case t == "map[]":
+		return "an empty map"
+	case isMap(t):
+		return "a map from " + plural(keyType(t)) + " to " + plural(valueType(t))
+	default:
+		return t
+	}
+}
+
+// kelkHint suggests the kelk form that reads a value of type t.
+func kelkHint(t string) string {
+	switch t {
+	case Int:
+		return "use kelk(x) ennam to read a whole number"
+	case Float:
+		return "use kelk(x) dashamsham to read a number with a decimal point"
+	case String:
+		return "use kelk(x) to read a string"
+	default:
+		return "kelk can only read into a new variable or one of the same type"
+	}
+}
@@ -0,0 +1,56 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// generateFunctionCode emits a top-level Go function using the signature the
+// type checker inferred for decl.
+func (g *generator) generateFunctionCode(decl ast.FunctionDeclaration) string {
+	vars := newScope(nil) // Functions cannot see the variables of main
+	params := make([]string, len(decl.ParamTypes))
+	for i, paramType := range decl.ParamTypes {
+		vars.declare(decl.Parameters[i], paramType)
+		params[i] = fmt.Sprintf("%s %s", goName(decl.Parameters[i]), paramType)
+	}
+	signature := fmt.Sprintf("func %s(%s)", goName(decl.Name), strings.Join(params, ", "))
+	if decl.ReturnType != "" {
+		signature += " " + decl.ReturnType
+	}
+
+	g.returnType = decl.ReturnType
+	body := g.generateBlockCode(decl.Body, vars)
+	g.returnType = ""
+	// Falling off the end of a function returns the zero value, but Go
+	// insists on an explicit return.
+	if decl.ReturnType != "" && !endsWithReturn(decl.Body) {
+		body += fmt.Sprintf("\treturn %s\n", zeroValue(decl.ReturnType))
+	}
+	return fmt.Sprintf("%s%s {\n%s}\n\n", lineDirective(decl), signature, body)
+}
+
+func endsWithReturn(statements []ast.ASTNode) bool {
+	if len(statements) == 0 {
+		return false
+	}
+	_, ok := statements[len(statements)-1].(ast.ReturnStatement)
+	return ok
+}
+
+func zeroValue(goType string) string {
+	switch goType {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "float64":
+		return "0.0"
+	case "int":
+		return "0"
+	default:
+		return "nil" // A list or map
+	}
+}
@@ -2,249 +2,398 @@ package codegen
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+	"github.com/Rohith04MVK/malang/diag"
 )
 
-// GenerateCode generates Go code from the AST.
+// generator holds the state shared by the whole translation: the signatures
+// of the program's functions, and the Go packages and runtime helper
+// functions the emitted code turns out to need.
+type generator struct {
+	functions  map[string]ast.FunctionDeclaration
+	returnType string // Return type of the function being generated
+	imports    map[string]bool
+	helpers    map[string]bool
+	labels     map[string]bool // Loop labels that a break or continue refers to
+}
+
+// GenerateCode generates Go code from the AST and panics if the program cannot
+// be translated. Use GenerateCodeDiag to get the problem back as a diagnostic.
 func GenerateCode(program ast.Program) string {
-	var code strings.Builder
+	code, _, err := GenerateCodeDiag(program)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
 
-	// Add package main
-	code.WriteString("package main\n\n")
+// GenerateCodeDiag generates Go code from the AST, reporting programs that
+// cannot be translated as diagnostics.
+func GenerateCodeDiag(program ast.Program) (generated string, diags []diag.Diagnostic, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			d, ok := r.(diag.Diagnostic)
+			if !ok {
+				panic(r)
+			}
+			generated, diags, err = "", []diag.Diagnostic{d}, d
+		}
+	}()
 
-	// Add import statements (conditionally)
-	needsFmt := false
-	needsStrconv := false
+	// Code is generated from the type checker's annotated copy of the program.
+	program, diags, err = check.Check(program)
+	if err != nil {
+		return "", diags, err
+	}
 
-	// Check if fmt or strconv are needed
+	g := &generator{
+		functions: make(map[string]ast.FunctionDeclaration),
+		imports:   make(map[string]bool),
+		helpers:   make(map[string]bool),
+		labels:    make(map[string]bool),
+	}
 	for _, statement := range program.Statements {
-		if usesFmt(statement) {
-			needsFmt = true
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			g.functions[decl.Name] = decl
 		}
-		if usesStrconv(statement) {
-			needsStrconv = true
+	}
+
+	// Function declarations become top-level Go functions; everything else
+	// goes into func main().
+	var functionsCode strings.Builder
+	var mainStatements []ast.ASTNode
+	for _, statement := range program.Statements {
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			functionsCode.WriteString(g.generateFunctionCode(decl))
+			continue
 		}
+		mainStatements = append(mainStatements, statement)
 	}
 
-	if needsFmt || needsStrconv {
-		code.WriteString("import (\n")
-		if needsFmt {
-			code.WriteString("\t\"fmt\"\n")
+	mainCode := g.generateBlockCode(mainStatements, newScope(nil))
+
+	var code strings.Builder
+
+	// Add package main
+	code.WriteString("package main\n\n")
+
+	// Add the imports the generated code turned out to use
+	if len(g.imports) > 0 {
+		packages := make([]string, 0, len(g.imports))
+		for pkg := range g.imports {
+			packages = append(packages, pkg)
 		}
-		if needsStrconv {
-			code.WriteString("\t\"strconv\"\n")
+		sort.Strings(packages)
+		code.WriteString("import (\n")
+		for _, pkg := range packages {
+			fmt.Fprintf(&code, "\t%q\n", pkg)
 		}
 		code.WriteString(")\n\n")
 	}
 
-	// Add func main() {
-	code.WriteString("func main() {\n")
-
-	declaredVars := make(map[string]string)
-
-	for _, statement := range program.Statements {
-		code.WriteString(generateStatementCode(statement, declaredVars))
+	helperNames := make([]string, 0, len(g.helpers))
+	for name := range g.helpers {
+		helperNames = append(helperNames, name)
 	}
+	sort.Strings(helperNames)
+	for _, name := range helperNames {
+		code.WriteString(helpers[name].code)
+	}
+
+	code.WriteString(functionsCode.String())
 
+	// Add func main() {
+	code.WriteString("func main() {\n")
+	code.WriteString(mainCode)
 	// Close func main() }
 	code.WriteString("}\n")
 
-	return code.String()
+	return code.String(), nil, nil
 }
 
-func generateStatementCode(statement ast.ASTNode, declaredVars map[string]string) string {
+func (g *generator) generateStatementCode(statement ast.ASTNode, vars *scope) string {
 	switch s := statement.(type) {
 	case ast.ParayuStatement:
-		return fmt.Sprintf("\tfmt.Println(%s)\n", generateExpressionCodeForParayu(s.Expression, declaredVars))
+		g.imports["fmt"] = true
+		code := g.generateExpressionCode(s.Expression, 0, vars)
+		if t := check.ExpressionType(s.Expression); t == "bool" || t == "float64" || isContainer(t) {
+			code = g.generateStringCode(s.Expression, 0, vars)
+		}
+		return fmt.Sprintf("\tfmt.Println(%s)\n", code)
 	case ast.KelkStatement:
-		if _, declared := declaredVars[s.Identifier]; declared {
-			panic(fmt.Sprintf("Variable '%s' already declared", s.Identifier))
+		if s.Type == "int" || s.Type == "float64" {
+			reader := "malangReadInt"
+			if s.Type == "float64" {
+				reader = "malangReadFloat"
+			}
+			g.useHelper(reader)
+			read := reader + "()"
+			if target, declared := vars.lookup(s.Identifier); declared {
+				if target == "float64" && s.Type == "int" {
+					read = "float64(" + read + ")"
+				}
+				return fmt.Sprintf("\t%s = %s\n", goName(s.Identifier), read)
+			}
+			vars.declare(s.Identifier, s.Type)
+			return fmt.Sprintf("\t%s := %s\n", goName(s.Identifier), read)
+		}
+		g.imports["fmt"] = true
+		if _, declared := vars.lookup(s.Identifier); declared {
+			return fmt.Sprintf("\tfmt.Scanln(&%s)\n", goName(s.Identifier))
 		}
-		declaredVars[s.Identifier] = "string"
-		return fmt.Sprintf("\tvar %s string\n\tfmt.Scanln(&%s)\n", s.Identifier, s.Identifier)
+		vars.declare(s.Identifier, "string")
+		return fmt.Sprintf("\tvar %s string\n\tfmt.Scanln(&%s)\n", goName(s.Identifier), goName(s.Identifier))
 	case ast.AssignmentStatement:
-		if _, declared := declaredVars[s.Identifier]; !declared {
-			inferredType := inferType(s.Expression, declaredVars)
-			declaredVars[s.Identifier] = inferredType
-			return fmt.Sprintf("\t%s := %s\n", s.Identifier, generateExpressionCode(s.Expression, 0, declaredVars))
-		} else {
-			return fmt.Sprintf("\t%s = %s\n", s.Identifier, generateExpressionCode(s.Expression, 0, declaredVars))
+		if target, declared := vars.lookup(s.Identifier); declared {
+			return fmt.Sprintf("\t%s = %s\n", goName(s.Identifier), g.generateConvertedCode(s.Expression, target, 0, vars))
 		}
+		vars.declare(s.Identifier, check.ExpressionType(s.Expression))
+		return fmt.Sprintf("\t%s := %s\n", goName(s.Identifier), g.generateExpressionCode(s.Expression, 0, vars))
 	case ast.IfStatement:
-		code := fmt.Sprintf("\tif %s {\n%s\t}", generateExpressionCode(s.Condition, 0, declaredVars), generateBlockCode(s.Body, declaredVars))
-		if s.ElseBody != nil {
-			code += fmt.Sprintf(" else {\n%s\t}\n", generateBlockCode(s.ElseBody, declaredVars))
+		// Each block is a scope of its own, as in the type checker, so
+		// variables first assigned inside it are not visible after it.
+		code := fmt.Sprintf("\tif %s {\n%s\t}", g.generateExpressionCode(s.Condition, 0, vars), g.generateBlockCode(s.Body, newScope(vars)))
+		switch {
+		case s.ElseIf:
+			// The chained if is generated on its own and joined on as a flat
+			// else if; its condition sees the same variables either way.
+			chained := g.generateStatementCode(s.ElseBody[0], vars)
+			code += " else " + inlineLineDirective(s.ElseBody[0]) + strings.TrimSpace(chained)
+		case s.ElseBody != nil:
+			code += fmt.Sprintf(" else {\n%s\t}", g.generateBlockCode(s.ElseBody, newScope(vars)))
 		}
-		return code
+		return code + "\n"
 	case ast.WhileStatement:
-		return fmt.Sprintf("\tfor %s {\n%s\t}\n", generateExpressionCode(s.Condition, 0, declaredVars), generateBlockCode(s.Body, declaredVars))
+		code := fmt.Sprintf("\tfor %s {\n%s\t}\n", g.generateExpressionCode(s.Condition, 0, vars), g.generateBlockCode(s.Body, newScope(vars)))
+		return g.labelled(s.Label, code)
 	case ast.ForStatement:
-		loopDeclaredVars := make(map[string]string)
-		for k, v := range declaredVars {
-			loopDeclaredVars[k] = v
-		}
-		loopDeclaredVars[s.Identifier] = "int"
-		startCode := generateExpressionCode(s.Start, 0, declaredVars)
-		endCode := generateExpressionCode(s.End, 0, declaredVars)
-		return fmt.Sprintf("\tfor %s := %s; %s <= %s; %s++ {\n%s\t}\n", s.Identifier, startCode, s.Identifier, endCode, s.Identifier, generateBlockCode(s.Body, loopDeclaredVars))
+		return g.labelled(s.Label, g.generateForCode(s, vars))
+	case ast.BreakStatement:
+		return g.generateLoopControlCode("break", s.Label)
+	case ast.ContinueStatement:
+		return g.generateLoopControlCode("continue", s.Label)
+	case ast.IndexAssignmentStatement:
+		target := g.generateExpressionCode(s.Target, 0, vars)
+		return fmt.Sprintf("\t%s = %s\n", target, g.generateConvertedCode(s.Value, s.Target.Type, 0, vars))
+	case ast.ReturnStatement:
+		if s.Expression == nil {
+			return "\treturn\n"
+		}
+		return fmt.Sprintf("\treturn %s\n", g.generateConvertedCode(s.Expression, g.returnType, 0, vars))
+	case ast.ExpressionStatement:
+		if call, isCall := s.Expression.(ast.CallExpression); isCall {
+			return fmt.Sprintf("\t%s\n", g.generateExpressionCode(call, 0, vars))
+		}
+		// Go only allows calls as statements, so discard any other value explicitly.
+		return fmt.Sprintf("\t_ = %s\n", g.generateExpressionCode(s.Expression, 0, vars))
+	case ast.FunctionDeclaration:
+		fail("function '%s' must be declared at the top level", s.Name)
+		return ""
 	default:
-		panic(fmt.Sprintf("Unexpected statement type: %T", statement))
+		fail("unexpected statement type: %T", statement)
+		return ""
 	}
 }
 
-func generateBlockCode(statements []ast.ASTNode, declaredVars map[string]string) string {
-	var code strings.Builder
-	for _, stmt := range statements {
-		//add tab to each statement.
-		code.WriteString(generateStatementCode(stmt, declaredVars))
+func (g *generator) generateBlockCode(statements []ast.ASTNode, vars *scope) string {
+	chunks := make([]string, len(statements))
+	declaredBy := make(map[string]int) // Statement that declared each variable
+	start := len(vars.order)           // Function parameters are already declared
+	for i, stmt := range statements {
+		declared := len(vars.order)
+		chunks[i] = lineDirective(stmt) + g.generateStatementCode(stmt, vars)
+		for _, name := range vars.order[declared:] {
+			declaredBy[name] = i
+		}
 	}
-	return code.String()
-}
-
-func generateExpressionCode(expression ast.ASTNode, parentPrecedence int, declaredVars map[string]string) string {
-	return generateExpressionCodeWithPrecedence(expression, parentPrecedence, false, declaredVars)
-}
 
-func generateExpressionCodeForParayu(expression ast.ASTNode, declaredVars map[string]string) string {
-	return generateExpressionCodeWithPrecedence(expression, 0, true, declaredVars)
+	// malang lets a variable go unused, but Go refuses to compile one, so
+	// mark those as used right after they are declared.
+	for _, name := range vars.order[start:] {
+		if !vars.used[name] {
+			chunks[declaredBy[name]] += fmt.Sprintf("\t_ = %s\n", goName(name))
+		}
+	}
+	return strings.Join(chunks, "")
 }
 
-func generateExpressionCodeWithPrecedence(expression ast.ASTNode, parentPrecedence int, isParayu bool, declaredVars map[string]string) string {
+func (g *generator) generateExpressionCode(expression ast.ASTNode, parentPrecedence int, vars *scope) string {
 	switch e := expression.(type) {
 	case ast.StringLiteral:
 		return fmt.Sprintf("%q", e.Value)
-	case ast.IntegerLiteral:
-		if isParayu {
-			return fmt.Sprintf("strconv.Itoa(%d)", e.Value)
+	case ast.InterpolatedString:
+		// The same code as joining the parts with '+': each expression is
+		// converted to a string according to its type.
+		precedence := operatorPrecedence("+")
+		var parts []string
+		for _, part := range e.Parts {
+			if text, ok := part.(ast.StringLiteral); ok && text.Value == "" {
+				continue
+			}
+			parts = append(parts, g.generateStringCode(part, precedence, vars))
+		}
+		switch {
+		case len(parts) == 0:
+			return `""`
+		case len(parts) == 1:
+			return parts[0]
+		case precedence <= parentPrecedence:
+			return "(" + strings.Join(parts, " + ") + ")"
+		default:
+			return strings.Join(parts, " + ")
 		}
+	case ast.IntegerLiteral:
 		return strconv.Itoa(e.Value)
+	case ast.FloatLiteral:
+		if parentPrecedence > 0 {
+			// An operand: Go would fold 0.1 + 0.2 or 1 / 0.0 exactly at
+			// compile time, where malang computes in float64 at run time.
+			g.useHelper("malangF")
+			return fmt.Sprintf("malangF(%s)", e.Text)
+		}
+		return e.Text
+	case ast.BooleanLiteral:
+		return strconv.FormatBool(e.Value)
 	case ast.Identifier:
-		if isParayu {
-			if declaredVars[e.Name] == "string" {
-				return e.Name
-			} else {
-				return fmt.Sprintf("strconv.Itoa(%s)", e.Name)
-			}
+		vars.use(e.Name)
+		return goName(e.Name)
+	case ast.UnaryExpression:
+		operand := g.generateExpressionCode(e.Operand, unaryPrecedence, vars)
+		if strings.HasPrefix(operand, "-") {
+			operand = "(" + operand + ")" // --x would be a decrement
+		}
+		return e.Operator + operand
+	case ast.ListLiteral:
+		return g.generateListCode(e, e.Type, vars)
+	case ast.MapLiteral:
+		return g.generateMapCode(e, e.Type, vars)
+	case ast.IndexExpression:
+		collection := g.generateExpressionCode(e.Collection, unaryPrecedence+1, vars)
+		index := g.generateExpressionCode(e.Index, 0, vars)
+		if strings.HasPrefix(check.ExpressionType(e.Collection), "map[") {
+			index = g.generateKeyCode(e.Collection, e.Index, vars)
+		} else if n, ok := intConstant(e.Index); ok && n < 0 {
+			// Go rejects a constant negative index when it compiles; the
+			// interpreter stops with an index out of range when it runs.
+			index = g.runtimeValue(index, "int")
 		}
-		return e.Name
+		return fmt.Sprintf("%s[%s]", collection, index)
+	case ast.CallExpression:
+		if code, ok := g.generateBuiltinCode(e, vars); ok {
+			return code
+		}
+		paramTypes := g.functions[e.Function].ParamTypes
+		arguments := make([]string, len(e.Arguments))
+		for i, argument := range e.Arguments {
+			arguments[i] = g.generateConvertedCode(argument, paramTypes[i], 0, vars)
+		}
+		return fmt.Sprintf("%s(%s)", goName(e.Function), strings.Join(arguments, ", "))
 	case ast.BinaryExpression:
+		if e.Operator == "**" {
+			// Go has no power operator. math.Pow works on floats, so the
+			// result of raising an int to an int is converted back.
+			g.imports["math"] = true
+			code := fmt.Sprintf("math.Pow(%s, %s)", g.generateConvertedCode(e.Left, "float64", 0, vars), g.generateConvertedCode(e.Right, "float64", 0, vars))
+			if e.Type == "int" {
+				return fmt.Sprintf("int(%s)", code)
+			}
+			return code
+		}
 		precedence := operatorPrecedence(e.Operator)
 
-		if e.Operator == "+" && isParayu {
-			leftCode := generateExpressionCodeWithPrecedence(e.Left, precedence, true, declaredVars)
-			rightCode := generateExpressionCodeWithPrecedence(e.Right, precedence, true, declaredVars)
-			return fmt.Sprintf("%s + %s", leftCode, rightCode)
-
+		var leftCode, rightCode string
+		if e.Operator == "+" && e.Type == "string" {
+			// String concatenation: operands that are not strings are converted.
+			leftCode = g.generateStringCode(e.Left, precedence, vars)
+			rightCode = g.generateStringCode(e.Right, precedence, vars)
 		} else { // Handle other operators (including -, *, /)
-			leftCode := generateExpressionCodeWithPrecedence(e.Left, precedence, isParayu, declaredVars)
-			rightCode := generateExpressionCodeWithPrecedence(e.Right, precedence, isParayu, declaredVars)
-
-			// Add parentheses based on precedence and associativity.
-			if precedence < parentPrecedence || (precedence == parentPrecedence && isLeftAssociative(e.Operator)) {
-				return fmt.Sprintf("(%s %s %s)", leftCode, e.Operator, rightCode)
+			// Mixing an int with a float promotes the int.
+			operandType := ""
+			if check.ExpressionType(e.Left) == "float64" || check.ExpressionType(e.Right) == "float64" {
+				operandType = "float64"
 			}
-			return fmt.Sprintf("%s %s %s", leftCode, e.Operator, rightCode)
+			leftCode = g.generateConvertedCode(e.Left, operandType, precedence, vars)
+			rightCode = g.generateConvertedCode(e.Right, operandType, precedence, vars)
+			// Go works out arithmetic on two int constants when it
+			// compiles, where overflowing or dividing by a constant 0 is
+			// an error rather than what the interpreter does at run time.
+			_, leftConstant := intConstant(e.Left)
+			divisor, rightConstant := intConstant(e.Right)
+			if leftConstant && rightConstant {
+				leftCode = g.runtimeValue(leftCode, operandType)
+			}
+			if leftConstant && rightConstant || rightConstant && divisor == 0 && (e.Operator == "/" || e.Operator == "%") {
+				rightCode = g.runtimeValue(rightCode, operandType)
+			}
+		}
+
+		// Add parentheses based on precedence and associativity.
+		if precedence < parentPrecedence || (precedence == parentPrecedence && isLeftAssociative(e.Operator)) {
+			return fmt.Sprintf("(%s %s %s)", leftCode, e.Operator, rightCode)
 		}
+		return fmt.Sprintf("%s %s %s", leftCode, e.Operator, rightCode)
 	default:
-		panic(fmt.Sprintf("Unexpected expression type: %T", expression))
+		fail("unexpected expression type: %T", expression)
+		return ""
 	}
 }
 
-func inferType(expression ast.ASTNode, declaredVars map[string]string) string {
-	switch e := expression.(type) {
-	case ast.StringLiteral:
-		return "string"
-	case ast.IntegerLiteral:
-		return "int"
-	case ast.Identifier:
-		if t, ok := declaredVars[e.Name]; ok {
-			return t
-		} else {
-			panic(fmt.Sprintf("Undeclared identifier %s during type inference", e.Name))
-		}
-	case ast.BinaryExpression: // Now handles more operators
-		if e.Operator == "+" {
-			leftType := inferType(e.Left, declaredVars)
-			rightType := inferType(e.Right, declaredVars)
-			if leftType == "string" || rightType == "string" {
-				return "string" // String concatenation
-			}
-		}
-		// For -, *, /, <, ==, assume int (more robust type checking is needed in a real compiler)
-		return "int"
-
-	default:
-		panic(fmt.Sprintf("Cannot infer type for expression: %T", expression))
+// runtimeValue passes a constant operand through malangI, or malangF if it
+// has been converted to a float, so that Go computes with it at run time.
+func (g *generator) runtimeValue(code, operandType string) string {
+	if operandType == "float64" {
+		g.useHelper("malangF")
+		return fmt.Sprintf("malangF(%s)", code)
 	}
+	g.useHelper("malangI")
+	return fmt.Sprintf("malangI(%s)", code)
 }
 
-func usesFmt(statement ast.ASTNode) bool {
-	switch s := statement.(type) {
-	case ast.ParayuStatement:
-		return true
-	case ast.KelkStatement:
-		return true
-	case ast.IfStatement:
-		return usesFmt(s.Condition) || blockUsesFmt(s.Body) || (s.ElseBody != nil && blockUsesFmt(s.ElseBody))
-	case ast.WhileStatement:
-		return usesFmt(s.Condition) || blockUsesFmt(s.Body)
-	case ast.ForStatement:
-		return usesFmt(s.Start) || usesFmt(s.End) || blockUsesFmt(s.Body)
-	case ast.BinaryExpression:
-		return usesFmt(s.Left) || usesFmt(s.Right)
+// generateStringCode generates expression as a Go string, converting it with
+// strconv if it has another type.
+func (g *generator) generateStringCode(expression ast.ASTNode, parentPrecedence int, vars *scope) string {
+	switch check.ExpressionType(expression) {
+	case "string":
+		return g.generateExpressionCode(expression, parentPrecedence, vars)
+	case "bool":
+		g.useHelper("malangBool")
+		return fmt.Sprintf("malangBool(%s)", g.generateExpressionCode(expression, 0, vars))
+	case "float64":
+		g.useHelper("malangFloat")
+		return fmt.Sprintf("malangFloat(%s)", g.generateExpressionCode(expression, 0, vars))
+	case "int":
+		g.imports["strconv"] = true
+		return fmt.Sprintf("strconv.Itoa(%s)", g.generateExpressionCode(expression, 0, vars))
 	default:
-		return false
+		// Lists and maps, whose elements may need converting themselves.
+		g.useHelper("malangFormat")
+		return fmt.Sprintf("malangFormat(%s)", g.generateExpressionCode(expression, 0, vars))
 	}
 }
-func blockUsesFmt(statements []ast.ASTNode) bool {
-	for _, stmt := range statements {
-		if usesFmt(stmt) {
-			return true
-		}
+
+// generateConvertedCode generates expression for a place that holds values
+// of type target, converting an int to float64 where a float is expected. A
+// list or map literal takes the type of the place, which fills in the types
+// of an empty one.
+func (g *generator) generateConvertedCode(expression ast.ASTNode, target string, parentPrecedence int, vars *scope) string {
+	if list, ok := expression.(ast.ListLiteral); ok && strings.HasPrefix(target, "[]") {
+		return g.generateListCode(list, target, vars)
 	}
-	return false
-}
-func usesStrconv(statement ast.ASTNode) bool {
-	switch s := statement.(type) {
-	case ast.ParayuStatement:
-		return expressionUsesStrconv(s.Expression) // Check expression within parayu
-	case ast.IfStatement:
-		return usesStrconv(s.Condition) || blockUsesStrconv(s.Body) || (s.ElseBody != nil && blockUsesStrconv(s.ElseBody))
-	case ast.WhileStatement:
-		return usesStrconv(s.Condition) || blockUsesStrconv(s.Body)
-	case ast.ForStatement:
-		return expressionUsesStrconv(s.Start) || expressionUsesStrconv(s.End) || blockUsesStrconv(s.Body)
-	case ast.AssignmentStatement:
-		return expressionUsesStrconv(s.Expression) //check if right side of assignment needs it
-	default:
-		return false
+	if m, ok := expression.(ast.MapLiteral); ok && strings.HasPrefix(target, "map[") {
+		return g.generateMapCode(m, target, vars)
 	}
-}
-func blockUsesStrconv(statements []ast.ASTNode) bool {
-	for _, stmt := range statements {
-		if usesStrconv(stmt) {
-			return true
-		}
+	if target == "float64" && check.ExpressionType(expression) == "int" {
+		return fmt.Sprintf("float64(%s)", g.generateExpressionCode(expression, 0, vars))
 	}
-	return false
+	return g.generateExpressionCode(expression, parentPrecedence, vars)
 }
 
-func expressionUsesStrconv(expression ast.ASTNode) bool {
-	switch e := expression.(type) {
-	case ast.IntegerLiteral:
-		return true // Integer literals within Parayu always need strconv
-	case ast.Identifier:
-		// Check if we have type information, default to false if unknown
-		if e.Type == "" {
-			return false
-		}
-		return e.Type == "int" // needs conversion if it is an int
-	case ast.BinaryExpression:
-		return expressionUsesStrconv(e.Left) || expressionUsesStrconv(e.Right)
-	default:
-		return false
-	}
+// isContainer reports whether goType is a slice or map type.
+func isContainer(goType string) bool {
+	return strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[")
 }
@@ -2,16 +2,79 @@ package codegen
 
 import (
 	"fmt"
-	"regexp"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
 )
 
+// unaryPrecedence binds prefix operators tighter than any binary operator.
+const unaryPrecedence = 6
+
+// fail aborts code generation with an error diagnostic; GenerateCodeDiag
+// recovers it.
+func fail(format string, args ...interface{}) {
+	panic(diag.Errorf(0, 0, 0, format, args...))
+}
+
+// lineDirective returns a //line comment that makes Go report errors and
+// panics in the code after it at the line node starts on in the malang
+// file. It is empty when the parser was not given a file name, as in the
+// REPL. Only the line is given: Go's columns do not match malang's.
+func lineDirective(node ast.ASTNode) string {
+	start := ast.SpanOf(node).Start
+	if start.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("//line %s:%d\n", start.File, start.Line)
+}
+
+// inlineLineDirective is lineDirective for code that continues on the same
+// Go line, such as the if of an else if.
+func inlineLineDirective(node ast.ASTNode) string {
+	start := ast.SpanOf(node).Start
+	if start.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("/*line %s:%d*/ ", start.File, start.Line)
+}
+
+// goName returns the Go identifier for a malang variable, function or
+// label. A name Go reserves or the generated code already uses, such as
+// range, len, fmt or malangFloat, gets a leading underscore. malang names
+// start with a letter, so the result cannot clash with another of them.
+func goName(name string) string {
+	if token.IsKeyword(name) || types.Universe.Lookup(name) != nil || goPackages[name] ||
+		strings.HasPrefix(name, "malang") || name == "main" || name == "init" {
+		return "_" + name
+	}
+	return name
+}
+
+// goPackages are the packages the generated code may import.
+var goPackages = func() map[string]bool {
+	packages := map[string]bool{"fmt": true, "math": true, "strconv": true}
+	for _, h := range helpers {
+		for _, pkg := range h.imports {
+			packages[pkg] = true
+		}
+	}
+	return packages
+}()
+
 func operatorPrecedence(operator string) int {
 	switch operator {
-	case "*", "/": // Multiplication and division have highest precedence
-		return 3
+	case "*", "/", "%": // Multiplication, division and remainder have highest precedence
+		return 5
 	case "+", "-": // Addition and subtraction
-		return 2
+		return 4
 	case "==", "<", ">", "<=", ">=", "!=": // Comparison - add missing operators
+		return 3
+	case "&&": // Logical and binds tighter than logical or, as in Go
+		return 2
+	case "||":
 		return 1
 	default:
 		// Match behavior with isLeftAssociative
@@ -22,15 +85,10 @@ func operatorPrecedence(operator string) int {
 
 func isLeftAssociative(operator string) bool {
 	switch operator {
-	case "+", "-", "*", "/", "==", "<", ">", "<=", ">=", "!=": // Add missing operators
+	case "+", "-", "*", "/", "%", "==", "<", ">", "<=", ">=", "!=", "&&", "||": // Add missing operators
 		return true
 	default:
 		fmt.Printf("Warning: Unknown operator in associativity check: %s, assuming left associative\n", operator)
 		return true
 	}
 }
-
-func RemoveComments(input string) string {
-	commentRegex := regexp.MustCompile(`//.*`)
-	return commentRegex.ReplaceAllString(input, "")
-}
@@ -0,0 +1,43 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// generateListCode emits a list literal as a Go slice literal of listType,
+// which is usually the literal's own type but may come from the variable an
+// empty list is stored in.
+func (g *generator) generateListCode(list ast.ListLiteral, listType string, vars *scope) string {
+	elementType := strings.TrimPrefix(listType, "[]")
+	elements := make([]string, len(list.Elements))
+	for i, element := range list.Elements {
+		elements[i] = g.generateConvertedCode(element, elementType, 0, vars)
+	}
+	return fmt.Sprintf("%s{%s}", listType, strings.Join(elements, ", "))
+}
+
+// generateBuiltinCode emits a call to one of malang's builtin functions, or
+// returns false if call is not one.
+func (g *generator) generateBuiltinCode(call ast.CallExpression, vars *scope) (string, bool) {
+	switch call.Function {
+	case "neelam":
+		return fmt.Sprintf("len(%s)", g.generateExpressionCode(call.Arguments[0], 0, vars)), true
+	case "cherkk":
+		list := g.generateExpressionCode(call.Arguments[0], 0, vars)
+		value := g.generateConvertedCode(call.Arguments[1], strings.TrimPrefix(call.Type, "[]"), 0, vars)
+		g.useHelper("malangAppend")
+		return fmt.Sprintf("malangAppend(%s, %s)", list, value), true
+	case "undo":
+		g.useHelper("malangHas")
+		m := g.generateExpressionCode(call.Arguments[0], 0, vars)
+		return fmt.Sprintf("malangHas(%s, %s)", m, g.generateKeyCode(call.Arguments[0], call.Arguments[1], vars)), true
+	case "kalay":
+		m := g.generateExpressionCode(call.Arguments[0], 0, vars)
+		return fmt.Sprintf("delete(%s, %s)", m, g.generateKeyCode(call.Arguments[0], call.Arguments[1], vars)), true
+	default:
+		return "", false
+	}
+}
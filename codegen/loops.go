@@ -0,0 +1,110 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+)
+
+func (g *generator) generateForCode(s ast.ForStatement, vars *scope) string {
+	// The loop variable lives in a scope of its own around the body.
+	loopVars := newScope(vars)
+	if s.Iterable != nil {
+		iterableType := check.ExpressionType(s.Iterable)
+		iterableCode := g.generateExpressionCode(s.Iterable, 0, vars)
+		loopVars.declare(s.Identifier, check.LoopVariableType(iterableType))
+		if strings.HasPrefix(iterableType, "map[") {
+			// Go visits map keys in random order; sort them so every run
+			// prints the same thing.
+			g.useHelper("malangKeys")
+			iterableCode = fmt.Sprintf("malangKeys(%s)", iterableCode)
+		}
+		body := g.generateBlockCode(s.Body, newScope(loopVars))
+		if !loopVars.used[s.Identifier] {
+			return fmt.Sprintf("\tfor range %s {\n%s\t}\n", iterableCode, body)
+		}
+		return fmt.Sprintf("\tfor _, %s := range %s {\n%s\t}\n", goName(s.Identifier), iterableCode, body)
+	}
+	loopVars.declare(s.Identifier, "int")
+	return g.generateRangeCode(s, vars, loopVars)
+}
+
+// generateRangeCode emits a loop over a range of integers as a three-clause
+// Go for loop, following the interpreter's execRange: the bounds and step
+// are evaluated once, in that order, and the step's sign decides whether the
+// loop counts up or down. When the step is written out as a number, the
+// direction is known and the loop reads like one written by hand.
+func (g *generator) generateRangeCode(s ast.ForStatement, vars, loopVars *scope) string {
+	i := goName(s.Identifier)
+	up, down := "<=", ">="
+	if s.Exclusive {
+		up, down = "<", ">"
+	}
+	startCode := g.generateExpressionCode(s.Start, 0, vars)
+	endCode := g.generateExpressionCode(s.End, 0, vars)
+
+	var header string
+	step, constant := 1, true
+	if s.Step != nil {
+		step, constant = intConstant(s.Step)
+	}
+	if constant {
+		init := fmt.Sprintf("%s := %s", i, startCode)
+		if _, literal := intConstant(s.End); !literal {
+			init = fmt.Sprintf("%s, malangEnd := %s, %s", i, startCode, endCode)
+			endCode = "malangEnd"
+		}
+		switch {
+		case step == 1:
+			header = fmt.Sprintf("%s; %s %s %s; %s++", init, i, up, endCode, i)
+		case step == -1:
+			header = fmt.Sprintf("%s; %s %s %s; %s--", init, i, down, endCode, i)
+		case step > 0:
+			header = fmt.Sprintf("%s; %s %s %s; %s += %d", init, i, up, endCode, i, step)
+		default:
+			header = fmt.Sprintf("%s; %s %s %s; %s -= %d", init, i, down, endCode, i, -step)
+		}
+	} else {
+		g.useHelper("malangCheckStep")
+		stepCode := g.generateExpressionCode(s.Step, 0, vars)
+		header = fmt.Sprintf("%s, malangEnd, malangStep := %s, %s, malangCheckStep(%s); malangStep > 0 && %s %s malangEnd || malangStep < 0 && %s %s malangEnd; %s += malangStep",
+			i, startCode, endCode, stepCode, i, up, i, down, i)
+	}
+	return fmt.Sprintf("\tfor %s {\n%s\t}\n", header, g.generateBlockCode(s.Body, newScope(loopVars)))
+}
+
+// intConstant returns the value of an integer written out in the source,
+// with or without a minus sign.
+func intConstant(expression ast.ASTNode) (int, bool) {
+	switch e := expression.(type) {
+	case ast.IntegerLiteral:
+		return e.Value, true
+	case ast.UnaryExpression:
+		if n, ok := intConstant(e.Operand); ok && e.Operator == "-" {
+			return -n, true
+		}
+	}
+	return 0, false
+}
+
+// generateLoopControlCode emits a break or continue. A label is remembered
+// so the loop it names is emitted with it.
+func (g *generator) generateLoopControlCode(keyword, label string) string {
+	if label == "" {
+		return fmt.Sprintf("\t%s\n", keyword)
+	}
+	g.labels[label] = true
+	return fmt.Sprintf("\t%s %s\n", keyword, goName(label))
+}
+
+// labelled puts label in front of the code for a loop. Go refuses to compile
+// a label that nothing refers to, so it is left out unless the body used it.
+func (g *generator) labelled(label, code string) string {
+	if !g.labels[label] {
+		return code
+	}
+	delete(g.labels, label)
+	return fmt.Sprintf("%s:\n%s", goName(label), code)
+}
@@ -0,0 +1,38 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+)
+
+// splitMapType returns the key and value types of a Go map type. Keys are
+// never containers, so the first ']' ends the key type.
+func splitMapType(mapType string) (key, value string) {
+	rest := strings.TrimPrefix(mapType, "map[")
+	end := strings.Index(rest, "]")
+	return rest[:end], rest[end+1:]
+}
+
+// generateMapCode emits a map literal as a Go map literal of mapType, which
+// is usually the literal's own type but may come from the variable an empty
+// map is stored in.
+func (g *generator) generateMapCode(literal ast.MapLiteral, mapType string, vars *scope) string {
+	keyType, valueType := splitMapType(mapType)
+	entries := make([]string, len(literal.Keys))
+	for i := range literal.Keys {
+		key := g.generateConvertedCode(literal.Keys[i], keyType, 0, vars)
+		value := g.generateConvertedCode(literal.Values[i], valueType, 0, vars)
+		entries[i] = fmt.Sprintf("%s: %s", key, value)
+	}
+	return fmt.Sprintf("%s{%s}", mapType, strings.Join(entries, ", "))
+}
+
+// generateKeyCode emits the key used to index the map expression collection,
+// converting an int key for a map with float keys.
+func (g *generator) generateKeyCode(collection, key ast.ASTNode, vars *scope) string {
+	keyType, _ := splitMapType(check.ExpressionType(collection))
+	return g.generateConvertedCode(key, keyType, 0, vars)
+}
@@ -0,0 +1,186 @@
+package codegen
+
+// helper is a small Go function copied into the generated program when the
+// code needs it, along with the packages it imports and the other helpers it
+// calls.
+type helper struct {
+	code    string
+	imports []string
+	uses    []string
+}
+
+var helpers = map[string]helper{
+	// malangBool spells a bool the malang way when it is printed.
+	"malangBool": {code: `func malangBool(b bool) string {
+	if b {
+		return "sheri"
+	}
+	return "thettu"
+}
+
+`},
+
+	// malangFloat prints a float in plain decimal, always with a fractional
+	// part so it reads as a float, and switches to an exponent only for very
+	// large or very small magnitudes. interp.formatFloat must match it.
+	"malangFloat": {code: `func malangFloat(f float64) string {
+	if abs := math.Abs(f); math.IsInf(f, 0) || math.IsNaN(f) || (abs != 0 && (abs < 1e-4 || abs >= 1e21)) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+`, imports: []string{"math", "strconv", "strings"}},
+
+	// malangF wraps a float literal that is an operand of an operator.
+	// Being a call rather than a constant, it makes Go do the arithmetic
+	// in float64 at run time, rounding, overflowing to +Inf and dividing
+	// by zero the way the interpreter does.
+	"malangF": {code: `func malangF(f float64) float64 {
+	return f
+}
+
+`},
+
+	// malangI wraps an int constant that is an operand of an operator
+	// whose other operand is a constant too, or a constant 0 divisor, for
+	// the same reason as malangF: so that Go does the arithmetic at run
+	// time, wrapping around on overflow and stopping on a division by 0.
+	"malangI": {code: `func malangI(n int) int {
+	return n
+}
+
+`},
+
+	// malangReadInt and malangReadFloat back kelk(x) ennam and
+	// kelk(x) dashamsham. They ask again until the input parses and stop the
+	// program if the input ends. interp.readNumber must match them.
+	"malangReadInt": {code: `func malangReadInt() int {
+	for {
+		var s string
+		if _, err := fmt.Scanln(&s); err == io.EOF {
+			fmt.Fprintln(os.Stderr, "error: kelk expected a whole number, but the input ended")
+			os.Exit(1)
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+		fmt.Printf("%q is not a whole number, try again\n", s)
+	}
+}
+
+`, imports: []string{"fmt", "io", "os", "strconv"}},
+
+	// malangCheckStep stops the program when the step of a range, worked
+	// out while it runs, is 0, as interp.execRange does.
+	"malangCheckStep": {code: `func malangCheckStep(step int) int {
+	if step == 0 {
+		fmt.Fprintln(os.Stderr, "error: the step of a range cannot be 0")
+		os.Exit(1)
+	}
+	return step
+}
+
+`, imports: []string{"fmt", "os"}},
+
+	"malangReadFloat": {code: `func malangReadFloat() float64 {
+	for {
+		var s string
+		if _, err := fmt.Scanln(&s); err == io.EOF {
+			fmt.Fprintln(os.Stderr, "error: kelk expected a number, but the input ended")
+			os.Exit(1)
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+		fmt.Printf("%q is not a number, try again\n", s)
+	}
+}
+
+`, imports: []string{"fmt", "io", "os", "strconv"}},
+
+	// malangFormat prints a list or map the way interp.formatValue does, with
+	// its elements separated by commas and spelled like parayu spells them.
+	// Map entries are printed in key order.
+	"malangFormat": {code: `func malangFormat(v interface{}) string {
+	switch v := v.(type) {
+	case bool:
+		return malangBool(v)
+	case float64:
+		return malangFloat(v)
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Slice:
+		elements := make([]string, rv.Len())
+		for i := range elements {
+			elements[i] = malangFormat(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			switch keys[i].Kind() {
+			case reflect.String:
+				return keys[i].String() < keys[j].String()
+			case reflect.Float64:
+				return keys[i].Float() < keys[j].Float()
+			default:
+				return keys[i].Int() < keys[j].Int()
+			}
+		})
+		entries := make([]string, len(keys))
+		for i, key := range keys {
+			entries[i] = malangFormat(key.Interface()) + ": " + malangFormat(rv.MapIndex(key).Interface())
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	}
+	return fmt.Sprint(v)
+}
+
+`, imports: []string{"fmt", "reflect", "sort", "strings"}, uses: []string{"malangBool", "malangFloat"}},
+
+	// malangKeys returns the keys of a map in order, for loops over a map.
+	// interp.sortedKeys must match it.
+	"malangKeys": {code: `func malangKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+`, imports: []string{"cmp", "slices"}},
+
+	// malangAppend backs cherkk. It copies the list first, so the new list
+	// never shares the old one's spare capacity and a later cherkk on the
+	// old list cannot change it. interp.callBuiltin must match it.
+	"malangAppend": {code: `func malangAppend[T any](list []T, value T) []T {
+	return append(list[:len(list):len(list)], value)
+}
+
+`},
+
+	// malangHas reports whether a map has a key, for undo.
+	"malangHas": {code: `func malangHas[K comparable, V any](m map[K]V, key K) bool {
+	_, ok := m[key]
+	return ok
+}
+
+`},
+}
+
+// useHelper records that the generated code calls the named helper.
+func (g *generator) useHelper(name string) {
+	g.helpers[name] = true
+	for _, pkg := range helpers[name].imports {
+		g.imports[pkg] = true
+	}
+	for _, other := range helpers[name].uses {
+		g.useHelper(other)
+	}
+}
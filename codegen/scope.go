@@ -0,0 +1,41 @@
+package codegen
+
+// scope is one level of the chain of Go scopes the generated code is nested
+// in. It mirrors the type checker's scopes: a variable is declared with :=
+// in the block where malang first assigns it, and assigning it again from a
+// nested block updates that same variable.
+type scope struct {
+	vars   map[string]string // Go type of each variable declared here
+	order  []string          // Variables in the order they were declared
+	used   map[string]bool   // Variables the generated code reads
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{vars: make(map[string]string), used: make(map[string]bool), parent: parent}
+}
+
+// lookup returns the Go type of the nearest variable called name.
+func (s *scope) lookup(name string) (string, bool) {
+	for sc := s; sc != nil; sc = sc.parent {
+		if t, ok := sc.vars[name]; ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+func (s *scope) declare(name, goType string) {
+	s.vars[name] = goType
+	s.order = append(s.order, name)
+}
+
+// use records that the generated code reads the nearest variable called name.
+func (s *scope) use(name string) {
+	for sc := s; sc != nil; sc = sc.parent {
+		if _, ok := sc.vars[name]; ok {
+			sc.used[name] = true
+			return
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package diag
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	case Note:
+		return "note"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// Diagnostic is a single message about the source, produced by any phase of
+// the compiler. Line and Col are 1-based; a zero Line means the message has no
+// position (for example, an error about the program as a whole).
+type Diagnostic struct {
+	Severity Severity
+	File     string
+	Line     int
+	Col      int
+	Span     int // Number of columns to underline, starting at Col
+	Message  string
+	Hint     string // Optional suggestion shown below the excerpt
+}
+
+// Errorf builds an error diagnostic at the given position.
+func Errorf(line, col, span int, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{Severity: Error, Line: line, Col: col, Span: span, Message: fmt.Sprintf(format, args...)}
+}
+
+// WithHint returns a copy of d carrying the given hint.
+func (d Diagnostic) WithHint(format string, args ...interface{}) Diagnostic {
+	d.Hint = fmt.Sprintf(format, args...)
+	return d
+}
+
+// Error formats the diagnostic as a single "file:line:col: severity: message" line.
+func (d Diagnostic) Error() string {
+	pos := d.File
+	if d.Line > 0 {
+		if pos != "" {
+			pos += ":"
+		}
+		pos += fmt.Sprintf("%d:%d", d.Line, d.Col)
+	}
+	if pos == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", pos, d.Severity, d.Message)
+}
+
+// HasErrors reports whether any diagnostic in ds is an error.
+func HasErrors(ds []Diagnostic) bool {
+	for _, d := range ds {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Err summarises the errors in ds as a single error value, or returns nil if
+// ds contains no errors.
+func Err(ds []Diagnostic) error {
+	var first *Diagnostic
+	count := 0
+	for i := range ds {
+		if ds[i].Severity != Error {
+			continue
+		}
+		if first == nil {
+			first = &ds[i]
+		}
+		count++
+	}
+	switch count {
+	case 0:
+		return nil
+	case 1:
+		return *first
+	default:
+		return fmt.Errorf("%s (and %d more errors)", first.Error(), count-1)
+	}
+}
+
+// SetFile fills in the file name of every diagnostic that does not have one.
+func SetFile(ds []Diagnostic, file string) {
+	for i := range ds {
+		if ds[i].File == "" {
+			ds[i].File = file
+		}
+	}
+}
+
+// Sort orders ds by position so that diagnostics from different phases read
+// top to bottom. Diagnostics without a position keep their relative order
+// at the end.
+func Sort(ds []Diagnostic) {
+	sort.SliceStable(ds, func(i, j int) bool {
+		a, b := ds[i], ds[j]
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+}
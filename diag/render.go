@@ -0,0 +1,68 @@
+package diag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Render writes ds to w in a rustc-like layout: the message, the position, the
+// offending source line and a row of carets under the reported span.
+//
+//	error: expected ')', got '-'
+//	  --> hello.malang:21:13
+//	   |
+//	21 | entho = (10 - 5) * 2
+//	   |             ^
+//	   = hint: ...
+func Render(w io.Writer, source string, ds []Diagnostic) {
+	lines := strings.Split(source, "\n")
+	for _, d := range ds {
+		fmt.Fprintf(w, "%s: %s\n", d.Severity, d.Message)
+		if d.Line <= 0 || d.Line > len(lines) {
+			if d.File != "" {
+				fmt.Fprintf(w, "  --> %s\n", d.File)
+			}
+			if d.Hint != "" {
+				fmt.Fprintf(w, "  = hint: %s\n", d.Hint)
+			}
+			fmt.Fprintln(w)
+			continue
+		}
+
+		lineNo := fmt.Sprint(d.Line)
+		gutter := strings.Repeat(" ", len(lineNo))
+		text := strings.TrimRight(lines[d.Line-1], "\r")
+
+		file := d.File
+		if file == "" {
+			file = "<input>"
+		}
+		fmt.Fprintf(w, "%s--> %s:%d:%d\n", gutter, file, d.Line, d.Col)
+		fmt.Fprintf(w, "%s |\n", gutter)
+		fmt.Fprintf(w, "%s | %s\n", lineNo, expandTabs(text))
+		fmt.Fprintf(w, "%s | %s%s\n", gutter, caretPadding(text, d.Col), strings.Repeat("^", max(d.Span, 1)))
+		if d.Hint != "" {
+			fmt.Fprintf(w, "%s = hint: %s\n", gutter, d.Hint)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// caretPadding returns the whitespace that lines the carets up under column
+// col of text, expanding tabs the same way as the excerpt so both stay aligned.
+func caretPadding(text string, col int) string {
+	var pad strings.Builder
+	for i := 0; i < col-1; i++ {
+		if i < len(text) && text[i] == '\t' {
+			pad.WriteString("    ")
+		} else {
+			pad.WriteByte(' ')
+		}
+	}
+	return pad.String()
+}
+
+func expandTabs(text string) string {
+	return strings.ReplaceAll(text, "\t", "    ")
+}
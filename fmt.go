@@ -0,0 +1,253 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/diag"
+	"github.com/Rohith04MVK/malang/format"
+)
+
+// formatFiles implements "malang fmt": it prints each file in canonical
+// form, or with -w rewrites the files and with -d shows what would change.
+func formatFiles(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "Write the result back to the file instead of printing it")
+	showDiff := flags.Bool("d", false, "Print a diff of the changes instead of the formatted source")
+	flags.Usage = func() {
+		fmt.Println("Usage: malang fmt [-w] [-d] <filename.malang>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	// Allow flags after the file names too: malang fmt prog.malang -w
+	var filenames []string
+	for flags.NArg() > 0 {
+		filenames = append(filenames, flags.Arg(0))
+		flags.Parse(flags.Args()[1:])
+	}
+	if len(filenames) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, filename := range filenames {
+		inputBytes, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading file:", err)
+			failed = true
+			continue
+		}
+		source := string(inputBytes)
+		formatted, diags, err := format.Source(source)
+		if err != nil {
+			// A file that does not parse is left alone.
+			diag.SetFile(diags, filename)
+			diag.Sort(diags)
+			diag.Render(os.Stderr, source, diags)
+			failed = true
+			continue
+		}
+
+		if *showDiff && formatted != source {
+			fmt.Print(unifiedDiff(filename, source, formatted))
+		}
+		if *write && formatted != source {
+			info, err := os.Stat(filename)
+			if err == nil {
+				err = os.WriteFile(filename, []byte(formatted), info.Mode().Perm())
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error writing file:", err)
+				failed = true
+			}
+		}
+		if !*write && !*showDiff {
+			fmt.Print(formatted)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// unifiedDiff shows the lines that differ between the old and new text of
+// a file, with three lines of context, in the format of diff -u.
+func unifiedDiff(filename, old, new string) string {
+	var edits []edit
+	diffLines(splitLines(old), splitLines(new), &edits)
+	// Within each run of changes put the removed lines first, as diff -u
+	// does, then number the lines.
+	for start := 0; start < len(edits); start++ {
+		end := start
+		for end < len(edits) && edits[end].op != ' ' {
+			end++
+		}
+		slices.SortStableFunc(edits[start:end], func(x, y edit) int {
+			return cmp.Compare(strings.IndexByte("-+", x.op), strings.IndexByte("-+", y.op))
+		})
+		start = end
+	}
+	i, j := 0, 0
+	for k := range edits {
+		edits[k].i, edits[k].j = i, j
+		if edits[k].op != '+' {
+			i++
+		}
+		if edits[k].op != '-' {
+			j++
+		}
+	}
+
+	const context = 3
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", filename, filename)
+	for start := 0; start < len(edits); {
+		// Find the next change and take the context around it, joining
+		// changes whose context would overlap into one hunk.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		end := start
+		for k := start; k < len(edits) && k <= end+2*context; k++ {
+			if edits[k].op != ' ' {
+				end = k
+			}
+		}
+		first, last := max(start-context, 0), min(end+context, len(edits)-1)
+
+		oldCount, newCount := 0, 0
+		for _, e := range edits[first : last+1] {
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+		}
+		// An empty side of a hunk is numbered by the line before it.
+		oldStart, newStart := edits[first].i, edits[first].j
+		if oldCount > 0 {
+			oldStart++
+		}
+		if newCount > 0 {
+			newStart++
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, e := range edits[first : last+1] {
+			fmt.Fprintf(&out, "%c%s\n", e.op, e.text)
+		}
+		start = last + 1
+	}
+	return out.String()
+}
+
+// edit is a line of a diff: kept (' '), removed ('-') or added ('+').
+type edit struct {
+	op   byte
+	text string
+	i, j int // Line numbers in the old and new text before this line
+}
+
+// diffLines appends to edits every line of a and b, marked as kept,
+// removed or added so that as many lines as possible are kept. Past the
+// lines the two start and end with, it finds the longest common
+// subsequence by Hirschberg's method, which takes memory in proportion to
+// the number of lines rather than to its square.
+func diffLines(a, b []string, edits *[]edit) {
+	add := func(op byte, lines []string) {
+		for _, line := range lines {
+			*edits = append(*edits, edit{op: op, text: line})
+		}
+	}
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	add(' ', a[:prefix])
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		add('-', a)
+		add('+', b)
+	case len(a) == 1:
+		if k := slices.Index(b, a[0]); k >= 0 {
+			add('+', b[:k])
+			add(' ', a)
+			add('+', b[k+1:])
+		} else {
+			add('-', a)
+			add('+', b)
+		}
+	default:
+		// Split a in half, and b where the longest common subsequences of
+		// the two halves with the two parts of b add up to the most.
+		mid := len(a) / 2
+		forward, backward := lcsLengths(a[:mid], b, false), lcsLengths(a[mid:], b, true)
+		split := 0
+		for k := range b {
+			if forward[k+1]+backward[len(b)-k-1] > forward[split]+backward[len(b)-split] {
+				split = k + 1
+			}
+		}
+		diffLines(a[:mid], b[:split], edits)
+		diffLines(a[mid:], b[split:], edits)
+	}
+	add(' ', common)
+}
+
+// lcsLengths returns, for each k, the length of the longest common
+// subsequence of a and the first k lines of b, or with reverse, of a and
+// the last k lines of b. It keeps only one row of the usual table.
+func lcsLengths(a, b []string, reverse bool) []int {
+	row := make([]int, len(b)+1)
+	for i := range a {
+		x := a[i]
+		if reverse {
+			x = a[len(a)-1-i]
+		}
+		diagonal := 0 // row[k-1] before this line of a
+		for k := 1; k <= len(b); k++ {
+			y := b[k-1]
+			if reverse {
+				y = b[len(b)-k]
+			}
+			above := row[k]
+			if x == y {
+				row[k] = diagonal + 1
+			} else {
+				row[k] = max(row[k], row[k-1])
+			}
+			diagonal = above
+		}
+	}
+	return row
+}
+
+// splitLines splits text into lines for diffing. A last line without a
+// newline carries diff's note saying so, which also makes it differ from
+// the same line with one.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if !strings.HasSuffix(text, "\n") {
+		lines[len(lines)-1] += "\n\\ No newline at end of file"
+	}
+	return lines
+}
@@ -0,0 +1,204 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/lexer"
+	"github.com/Rohith04MVK/malang/parser"
+)
+
+// Precedence levels above the binary operators, matching the parser: ** binds
+// tighter than any of them, then a prefix operator, then indexing.
+const (
+	powerPrecedence   = 6
+	unaryPrecedence   = 7
+	postfixPrecedence = 8
+)
+
+func precedence(expression ast.ASTNode) int {
+	switch e := expression.(type) {
+	case ast.BinaryExpression:
+		if e.Operator == "**" {
+			return powerPrecedence
+		}
+		return parser.Precedence(e.Operator)
+	case ast.UnaryExpression:
+		return unaryPrecedence
+	default:
+		return postfixPrecedence
+	}
+}
+
+// operand prints expression where only operators of at least min
+// precedence can appear without parentheses.
+func (p *printer) operand(expression ast.ASTNode, min int) string {
+	if precedence(expression) < min {
+		return "(" + p.expr(expression) + ")"
+	}
+	return p.expr(expression)
+}
+
+// expr prints expression, with the comments that came before it in the
+// source in front of it, so that a comment inside an expression stays by
+// the node after it.
+func (p *printer) expr(expression ast.ASTNode) string {
+	return p.inline(ast.SpanOf(expression).Start.Offset) + p.node(expression)
+}
+
+func (p *printer) node(expression ast.ASTNode) string {
+	span := ast.SpanOf(expression)
+	switch e := expression.(type) {
+	case ast.StringLiteral:
+		return e.Text
+	case ast.InterpolatedString:
+		code := `"`
+		for i, part := range e.Parts {
+			if i%2 == 0 {
+				code += part.(ast.StringLiteral).Text
+			} else {
+				// The text after the expression starts at its '}'.
+				code += "{" + p.expr(part) + p.closing(ast.SpanOf(e.Parts[i+1]).Start.Offset) + "}"
+			}
+		}
+		return code + `"`
+	case ast.IntegerLiteral:
+		return strconv.Itoa(e.Value)
+	case ast.FloatLiteral:
+		return e.Text
+	case ast.BooleanLiteral:
+		if e.Value {
+			return "sheri"
+		}
+		return "thettu"
+	case ast.Identifier:
+		return e.Name
+	case ast.BinaryExpression:
+		if e.Operator == "**" {
+			// The base is a single operand; the exponent may itself be a
+			// power, since ** groups to the right, or start with a sign.
+			return p.operand(e.Left, postfixPrecedence) + " ** " + p.operand(e.Right, powerPrecedence)
+		}
+		min := parser.Precedence(e.Operator)
+		// Operators of equal precedence group to the left, so only the
+		// right operand needs parentheses around one.
+		return p.operand(e.Left, min) + " " + e.Operator + " " + p.operand(e.Right, min+1)
+	case ast.UnaryExpression:
+		code := p.operand(e.Operand, powerPrecedence)
+		if e.Operator == "-" && strings.HasPrefix(code, "-") {
+			code = "(" + code + ")"
+		}
+		return e.Operator + code
+	case ast.IndexExpression:
+		return p.operand(e.Collection, postfixPrecedence) + "[" + p.expr(e.Index) + "]"
+	case ast.CallExpression:
+		return e.Function + "(" + p.list(e.Arguments, span) + ")"
+	case ast.ListLiteral:
+		return "[" + p.list(e.Elements, span) + "]"
+	case ast.MapLiteral:
+		spans := make([]ast.Span, len(e.Keys))
+		for i := range e.Keys {
+			spans[i] = ast.Span{Start: ast.SpanOf(e.Keys[i]).Start, End: ast.SpanOf(e.Values[i]).End}
+		}
+		return "{" + p.items(spans, span, func(i int) string {
+			return p.expr(e.Keys[i]) + ": " + p.expr(e.Values[i])
+		}) + "}"
+	default:
+		return ""
+	}
+}
+
+// list prints the elements of a list literal or the arguments of a call
+// that spans whole in the source.
+func (p *printer) list(expressions []ast.ASTNode, whole ast.Span) string {
+	spans := make([]ast.Span, len(expressions))
+	for i, expression := range expressions {
+		spans[i] = ast.SpanOf(expression)
+	}
+	return p.items(spans, whole, func(i int) string { return p.expr(expressions[i]) })
+}
+
+// items prints the comma-separated items of a list, map or call that
+// spans whole in the source. The items span spans and are printed by item.
+// Normally they share a line, but if the source ran over several lines
+// and had comments among them, they go one to a line, each with its
+// comments, as the source most likely had them.
+func (p *printer) items(spans []ast.Span, whole ast.Span, item func(i int) string) string {
+	end := whole.End.Offset - 1 // The closing bracket
+	commented := len(p.comments) > 0 && p.comments[0].Offset < end
+	if !commented || whole.Start.Line == whole.End.Line {
+		codes := make([]string, len(spans))
+		for i := range spans {
+			codes[i] = item(i)
+		}
+		if len(codes) == 0 {
+			return strings.TrimPrefix(p.closing(end), " ")
+		}
+		return strings.Join(codes, ", ") + p.closing(end)
+	}
+
+	p.depth++
+	margin := strings.Repeat(indent, p.depth)
+	code := "\n"
+	for i, span := range spans {
+		for _, comment := range p.take(span.Start.Offset) {
+			code += margin + commentText(comment) + "\n"
+		}
+		code += margin + item(i)
+		if i < len(spans)-1 {
+			code += ","
+		}
+		for len(p.comments) > 0 && p.comments[0].Offset < end && p.comments[0].Line == span.End.Line {
+			code += " " + commentText(p.comments[0])
+			p.comments = p.comments[1:]
+		}
+		code += "\n"
+	}
+	for _, comment := range p.take(end) {
+		code += margin + commentText(comment) + "\n"
+	}
+	p.depth--
+	return code + strings.Repeat(indent, p.depth)
+}
+
+// inline returns the comments before offset, to be written in front of
+// what is there. A line comment ends the line, so what follows it goes on
+// the next line, a level deeper.
+func (p *printer) inline(offset int) string {
+	code := ""
+	for _, comment := range p.take(offset) {
+		code += commentText(comment)
+		if strings.HasPrefix(comment.Text, "//") {
+			code += "\n" + strings.Repeat(indent, p.depth+1)
+		} else {
+			code += " "
+		}
+	}
+	return code
+}
+
+// closing returns the comments before offset, to be written after what
+// came before them and in front of a closing bracket there.
+func (p *printer) closing(offset int) string {
+	code := ""
+	for _, comment := range p.take(offset) {
+		code += " " + commentText(comment)
+		if strings.HasPrefix(comment.Text, "//") {
+			code += "\n" + strings.Repeat(indent, p.depth)
+		}
+	}
+	return code
+}
+
+// take removes the comments before offset from those waiting to be
+// printed, and returns them.
+func (p *printer) take(offset int) []lexer.Comment {
+	n := 0
+	for n < len(p.comments) && p.comments[n].Offset < offset {
+		n++
+	}
+	taken := p.comments[:n]
+	p.comments = p.comments[n:]
+	return taken
+}
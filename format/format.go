@@ -0,0 +1,130 @@
+package format
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
+	"github.com/Rohith04MVK/malang/lexer"
+	"github.com/Rohith04MVK/malang/parser"
+)
+
+// indent is one level of block indentation.
+const indent = "    "
+
+// Source formats a malang program: one statement per line, blocks indented
+// by four spaces with their opening brace on the line of the statement that
+// owns them, single spaces around binary operators and after commas, and
+// no more than one blank line in a row. Comments are kept where they were.
+// A program that does not lex or parse is returned unchanged, with the
+// problems as diagnostics.
+func Source(source string) (string, []diag.Diagnostic, error) {
+	tokens, lexDiags, lexErr := lexer.LexDiag(source)
+	program, parseDiags, parseErr := parser.NewParser(tokens).ParseDiag()
+	if lexErr != nil || parseErr != nil {
+		diags := append(lexDiags, parseDiags...)
+		return source, diags, diag.Err(diags)
+	}
+
+	p := &printer{tokens: tokens}
+	for _, token := range tokens {
+		p.comments = append(p.comments, token.Comments...)
+	}
+	p.statements(program.Statements, len(source)+1)
+	return p.out.String(), nil, nil
+}
+
+// printer writes out a program's statements, fitting in the comments
+// between them by their offsets in the source.
+type printer struct {
+	out      strings.Builder
+	depth    int             // Block nesting of the line being written
+	tokens   []lexer.Token   // For finding the braces that close blocks
+	comments []lexer.Comment // Comments not printed yet, in source order
+	last     int             // Source line of what was printed last, 0 at the start of a block
+}
+
+// statements prints a block's statements and the comments before the brace
+// that closes it, at offset end.
+func (p *printer) statements(statements []ast.ASTNode, end int) {
+	for _, statement := range statements {
+		start := ast.SpanOf(statement).Start
+		p.leading(start.Offset)
+		p.separate(start.Line)
+		p.statement(statement)
+	}
+	p.leading(end)
+}
+
+// leading prints the comments that come before offset, each on a line of
+// its own.
+func (p *printer) leading(offset int) {
+	for len(p.comments) > 0 && p.comments[0].Offset < offset {
+		comment := p.comments[0]
+		p.comments = p.comments[1:]
+		p.separate(comment.Line)
+		p.line(commentText(comment), endLine(comment))
+	}
+}
+
+// commentText is a comment as it is printed, without trailing blanks.
+func commentText(comment lexer.Comment) string {
+	return strings.TrimRight(comment.Text, " \t")
+}
+
+// endLine is the source line a comment ends on; a block comment may run
+// over several.
+func endLine(comment lexer.Comment) int {
+	return comment.Line + strings.Count(comment.Text, "\n")
+}
+
+// separate keeps one blank line before something that had at least one
+// above it in the source, except at the start of a block.
+func (p *printer) separate(line int) {
+	if p.last != 0 && line > p.last+1 {
+		p.out.WriteString("\n")
+	}
+}
+
+// line writes text as a line of its own at the current depth. Comments
+// that followed it on the same source line go at its end.
+func (p *printer) line(text string, sourceLine int) {
+	p.out.WriteString(strings.Repeat(indent, p.depth))
+	p.out.WriteString(text)
+	p.last = sourceLine
+	for len(p.comments) > 0 && p.comments[0].Line == sourceLine {
+		p.out.WriteString(" " + commentText(p.comments[0]))
+		p.last = endLine(p.comments[0])
+		p.comments = p.comments[1:]
+	}
+	p.out.WriteString("\n")
+}
+
+// block prints the statements of a block one level deeper, and returns the
+// brace that closes it. open is an offset after the end of the statement's
+// header, before the block's opening brace.
+func (p *printer) block(statements []ast.ASTNode, open int) lexer.Token {
+	after := p.find(lexer.TokLBrace, open).EndOffset
+	if len(statements) > 0 {
+		after = ast.SpanOf(statements[len(statements)-1]).End.Offset
+	}
+	closing := p.find(lexer.TokRBrace, after)
+
+	p.depth++
+	p.last = 0
+	p.statements(statements, closing.Offset)
+	p.depth--
+	return closing
+}
+
+// find returns the first token of the given kind at or after offset.
+func (p *printer) find(kind lexer.Kind, offset int) lexer.Token {
+	i := sort.Search(len(p.tokens), func(i int) bool { return p.tokens[i].Offset >= offset })
+	for ; i < len(p.tokens); i++ {
+		if p.tokens[i].Kind == kind {
+			return p.tokens[i]
+		}
+	}
+	return p.tokens[len(p.tokens)-1]
+}
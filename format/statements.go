@@ -0,0 +1,115 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// inputWords are the words written after kelk(...) for each type of input.
+var inputWords = map[string]string{
+	"int":     "ennam",
+	"float64": "dashamsham",
+}
+
+func (p *printer) statement(statement ast.ASTNode) {
+	span := ast.SpanOf(statement)
+	switch s := statement.(type) {
+	case ast.ParayuStatement:
+		p.line("parayu("+p.expr(s.Expression)+")", span.End.Line)
+	case ast.KelkStatement:
+		code := "kelk(" + s.Identifier + ")"
+		if word, ok := inputWords[s.Type]; ok {
+			code += " " + word
+		}
+		p.line(code, span.End.Line)
+	case ast.AssignmentStatement:
+		p.line(p.assignment(s.Identifier, s.Expression, s.Compound), span.End.Line)
+	case ast.IndexAssignmentStatement:
+		p.line(p.assignment(p.expr(s.Target), s.Value, s.Compound), span.End.Line)
+	case ast.IfStatement:
+		p.ifStatement(s, "", span.Start.Line)
+	case ast.WhileStatement:
+		header := "ellam_sheriyano (" + p.expr(s.Condition) + ") enkil {"
+		p.line(labelled(s.Label, header), span.Start.Line)
+		closing := p.block(s.Body, ast.SpanOf(s.Condition).End.Offset)
+		p.line("}", closing.Line)
+	case ast.ForStatement:
+		var loop string
+		var header ast.ASTNode // Last expression before the body
+		if s.Iterable != nil {
+			loop, header = p.expr(s.Iterable), s.Iterable
+		} else {
+			dots := ".."
+			if s.Exclusive {
+				dots = "..<"
+			}
+			loop, header = p.expr(s.Start)+dots+p.expr(s.End), s.End
+			if s.Step != nil {
+				loop, header = loop+" idavittu "+p.expr(s.Step), s.Step
+			}
+		}
+		code := "oron_ayi " + s.Identifier + " edukk (" + loop + ") {"
+		p.line(labelled(s.Label, code), span.Start.Line)
+		closing := p.block(s.Body, ast.SpanOf(header).End.Offset)
+		p.line("}", closing.Line)
+	case ast.FunctionDeclaration:
+		p.line("pani "+s.Name+"("+strings.Join(s.Parameters, ", ")+") {", span.Start.Line)
+		closing := p.block(s.Body, s.Pos.Offset)
+		p.line("}", closing.Line)
+	case ast.ReturnStatement:
+		code := "thirich_kodukk"
+		if s.Expression != nil {
+			code += " " + p.expr(s.Expression)
+		}
+		p.line(code, span.End.Line)
+	case ast.BreakStatement:
+		p.line(withLabel("nirthu", s.Label), span.End.Line)
+	case ast.ContinueStatement:
+		p.line(withLabel("thudaru", s.Label), span.End.Line)
+	case ast.ExpressionStatement:
+		p.line(p.expr(s.Expression), span.End.Line)
+	}
+}
+
+// ifStatement prints an if statement and its else branches. An else-if is
+// printed by the same function with "} alle " in front of it, so a chain
+// stays flat however long it is. line is the source line the first line
+// printed stands for.
+func (p *printer) ifStatement(s ast.IfStatement, prefix string, line int) {
+	p.line(prefix+"ith_sheriyano ("+p.expr(s.Condition)+") enkil {", line)
+	closing := p.block(s.Body, ast.SpanOf(s.Condition).End.Offset)
+	switch {
+	case s.ElseIf:
+		p.ifStatement(s.ElseBody[0].(ast.IfStatement), "} alle ", closing.Line)
+	case s.ElseBody != nil:
+		p.line("} alle {", closing.Line)
+		closing = p.block(s.ElseBody, closing.EndOffset)
+		p.line("}", closing.Line)
+	default:
+		p.line("}", closing.Line)
+	}
+}
+
+// assignment prints an assignment to target, putting a compound assignment
+// back the way it was written: x = x + v is printed as x += v.
+func (p *printer) assignment(target string, value ast.ASTNode, compound bool) string {
+	if binary, ok := value.(ast.BinaryExpression); ok && compound {
+		return target + " " + binary.Operator + "= " + p.expr(binary.Right)
+	}
+	return target + " = " + p.expr(value)
+}
+
+func labelled(label, code string) string {
+	if label == "" {
+		return code
+	}
+	return label + ": " + code
+}
+
+func withLabel(keyword, label string) string {
+	if label == "" {
+		return keyword
+	}
+	return keyword + " " + label
+}
@@ -0,0 +1,58 @@
+package interp
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// returnSignal unwinds execution from a thirich_kodukk back to the call that
+// is running the function. It travels as an error so that every statement
+// in between stops, but it never escapes call.
+type returnSignal struct {
+	value    Value
+	hasValue bool
+}
+
+func (returnSignal) Error() string { return "thirich_kodukk outside of a function" }
+
+// call runs a function and returns its result. Functions only see their own
+// parameters and locals, like the top-level Go functions codegen emits. If
+// the declaration carries the signature inferred by the type checker,
+// arguments and results are promoted to it.
+func (it *Interpreter) call(call ast.CallExpression, scope *env) (returnSignal, error) {
+	if v, ok, err := it.callBuiltin(call, scope); ok {
+		return returnSignal{value: v, hasValue: true}, err
+	}
+	decl, ok := it.functions[call.Function]
+	if !ok {
+		return returnSignal{}, runtimeError(call, "undefined function '%s'", call.Function)
+	}
+	if len(call.Arguments) != len(decl.Parameters) {
+		return returnSignal{}, runtimeError(call, "function '%s' takes %d arguments, got %d", call.Function, len(decl.Parameters), len(call.Arguments))
+	}
+
+	frame := newEnv(nil)
+	for i, argument := range call.Arguments {
+		v, err := it.eval(argument, scope)
+		if err != nil {
+			return returnSignal{}, err
+		}
+		if i < len(decl.ParamTypes) {
+			v = promote(v, decl.ParamTypes[i])
+		}
+		frame.define(decl.Parameters[i], v)
+	}
+
+	// The result is promoted to the type the checker gave the call, which
+	// is the function's return type.
+	err := it.execBlock(decl.Body, frame)
+	if ret, ok := err.(returnSignal); ok {
+		ret.value = promote(ret.value, call.Type)
+		return ret, nil
+	}
+	if err == nil && call.Type != "" {
+		// Falling off the end of a function that returns a value gives
+		// the zero value of its return type, as in the generated Go.
+		return returnSignal{value: zeroValue(call.Type), hasValue: true}, nil
+	}
+	return returnSignal{}, err
+}
@@ -0,0 +1,40 @@
+package interp
+
+// env is one level of the variable scope chain. Every block gets its own env
+// whose parent is the enclosing one, matching the scoping of the Go code the
+// code generator emits.
+type env struct {
+	vars   map[string]Value
+	parent *env
+}
+
+func newEnv(parent *env) *env {
+	return &env{vars: make(map[string]Value), parent: parent}
+}
+
+func (e *env) lookup(name string) (Value, bool) {
+	for scope := e; scope != nil; scope = scope.parent {
+		if v, ok := scope.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// assign updates name in the nearest scope that already defines it, or
+// defines it in e if no enclosing scope does. A variable keeps the type of
+// its first value, so an int stored into a float variable becomes a float.
+func (e *env) assign(name string, v Value) {
+	for scope := e; scope != nil; scope = scope.parent {
+		if old, ok := scope.vars[name]; ok {
+			scope.vars[name] = promote(v, TypeName(old))
+			return
+		}
+	}
+	e.vars[name] = v
+}
+
+// define creates name in e, shadowing any outer variable of the same name.
+func (e *env) define(name string, v Value) {
+	e.vars[name] = v
+}
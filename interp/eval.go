@@ -0,0 +1,263 @@
+package interp
+
+import (
+	"math"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
+)
+
+func (it *Interpreter) eval(expression ast.ASTNode, scope *env) (Value, error) {
+	switch e := expression.(type) {
+	case ast.StringLiteral:
+		return e.Value, nil
+	case ast.InterpolatedString:
+		var text strings.Builder
+		for _, part := range e.Parts {
+			v, err := it.eval(part, scope)
+			if err != nil {
+				return nil, err
+			}
+			text.WriteString(formatValue(v))
+		}
+		return text.String(), nil
+	case ast.IntegerLiteral:
+		return e.Value, nil
+	case ast.FloatLiteral:
+		return e.Value, nil
+	case ast.BooleanLiteral:
+		return e.Value, nil
+	case ast.Identifier:
+		if v, ok := scope.lookup(e.Name); ok {
+			return v, nil
+		}
+		return nil, runtimeError(e, "undeclared identifier '%s'", e.Name)
+	case ast.CallExpression:
+		ret, err := it.call(e, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !ret.hasValue {
+			return nil, runtimeError(e, "function '%s' did not return a value", e.Function)
+		}
+		return ret.value, nil
+	case ast.ListLiteral:
+		return it.evalList(e, scope)
+	case ast.MapLiteral:
+		return it.evalMap(e, scope)
+	case ast.IndexExpression:
+		return it.evalIndexExpression(e, scope)
+	case ast.UnaryExpression:
+		operand, err := it.eval(e.Operand, scope)
+		if err != nil {
+			return nil, err
+		}
+		switch v := operand.(type) {
+		case bool:
+			if e.Operator == "!" {
+				return !v, nil
+			}
+		case int:
+			if e.Operator == "-" {
+				return -v, nil
+			}
+		case float64:
+			if e.Operator == "-" {
+				return -v, nil
+			}
+		}
+		return nil, diag.Errorf(e.Pos.Line, e.Pos.Col, len(e.Operator), "invalid operation: %s%s", e.Operator, TypeName(operand))
+	case ast.BinaryExpression:
+		if e.Operator == "&&" || e.Operator == "||" {
+			return it.evalLogical(e, scope)
+		}
+		left, err := it.eval(e.Left, scope)
+		if err != nil {
+			return nil, err
+		}
+		right, err := it.eval(e.Right, scope)
+		if err != nil {
+			return nil, err
+		}
+		return binaryOp(e, left, right)
+	default:
+		return nil, runtimeError(expression, "unexpected expression type: %T", expression)
+	}
+}
+
+// evalLogical evaluates && and ||, skipping the right operand when the left
+// one already decides the result.
+func (it *Interpreter) evalLogical(e ast.BinaryExpression, scope *env) (Value, error) {
+	left, err := it.evalCondition(e.Left, scope)
+	if err != nil {
+		return nil, err
+	}
+	if (e.Operator == "&&" && !left) || (e.Operator == "||" && left) {
+		return left, nil
+	}
+	return it.evalCondition(e.Right, scope)
+}
+
+func (it *Interpreter) evalCondition(expression ast.ASTNode, scope *env) (bool, error) {
+	v, err := it.eval(expression, scope)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, runtimeError(expression, "condition must be a bool, got %s", TypeName(v))
+	}
+	return b, nil
+}
+
+func (it *Interpreter) evalInt(expression ast.ASTNode, scope *env, what string) (int, error) {
+	v, err := it.eval(expression, scope)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(int)
+	if !ok {
+		return 0, runtimeError(expression, "%s must be an int, got %s", what, TypeName(v))
+	}
+	return n, nil
+}
+
+// binaryOp applies the operator of e to the values of its operands. Errors
+// point at the operator.
+func binaryOp(e ast.BinaryExpression, left, right Value) (Value, error) {
+	operator := e.Operator
+	fail := func(format string, args ...interface{}) error {
+		return diag.Errorf(e.Pos.Line, e.Pos.Col, len(operator), format, args...)
+	}
+	// '+' with a string on either side is concatenation, as in parayu("Count: " + ennam).
+	if operator == "+" {
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		if leftIsString || rightIsString {
+			return formatValue(left) + formatValue(right), nil
+		}
+	}
+
+	// Mixing an int with a float promotes the int.
+	if lf, rf, ok := asFloats(left, right); ok {
+		switch operator {
+		case "+":
+			return lf + rf, nil
+		case "-":
+			return lf - rf, nil
+		case "*":
+			return lf * rf, nil
+		case "/":
+			return lf / rf, nil
+		case "**":
+			return math.Pow(lf, rf), nil
+		case "==":
+			return lf == rf, nil
+		case "!=":
+			return lf != rf, nil
+		case "<":
+			return lf < rf, nil
+		case ">":
+			return lf > rf, nil
+		case "<=":
+			return lf <= rf, nil
+		case ">=":
+			return lf >= rf, nil
+		}
+	}
+
+	switch l := left.(type) {
+	case int:
+		r, ok := right.(int)
+		if !ok {
+			break
+		}
+		switch operator {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			if r == 0 {
+				return nil, fail("integer divide by zero")
+			}
+			return l / r, nil
+		case "%":
+			if r == 0 {
+				return nil, fail("integer divide by zero")
+			}
+			return l % r, nil
+		case "**":
+			// Computed the way the generated Go code does, through floats.
+			return int(math.Pow(float64(l), float64(r))), nil
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case ">":
+			return l > r, nil
+		case "<=":
+			return l <= r, nil
+		case ">=":
+			return l >= r, nil
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			break
+		}
+		switch operator {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			break
+		}
+		switch operator {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case ">":
+			return l > r, nil
+		case "<=":
+			return l <= r, nil
+		case ">=":
+			return l >= r, nil
+		}
+	}
+	return nil, fail("invalid operation: %s %s %s", TypeName(left), operator, TypeName(right))
+}
+
+// asFloats returns both operands as floats if at least one is a float and
+// the other is a number.
+func asFloats(left, right Value) (float64, float64, bool) {
+	toFloat := func(v Value) (float64, bool) {
+		switch n := v.(type) {
+		case int:
+			return float64(n), true
+		case float64:
+			return n, true
+		}
+		return 0, false
+	}
+	_, leftIsFloat := left.(float64)
+	_, rightIsFloat := right.(float64)
+	if !leftIsFloat && !rightIsFloat {
+		return 0, 0, false
+	}
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	return lf, rf, lok && rok
+}
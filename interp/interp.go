@@ -0,0 +1,220 @@
+package interp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
+)
+
+// Interpreter evaluates malang programs directly from the AST, without
+// generating Go code. Variables assigned at the top level persist across
+// calls to Run, which is what the REPL relies on.
+type Interpreter struct {
+	globals   *env
+	functions map[string]ast.FunctionDeclaration
+	in        *bufio.Reader
+	out       io.Writer
+}
+
+// New creates an interpreter that reads kelk input from in and writes parayu
+// output to out.
+func New(in io.Reader, out io.Writer) *Interpreter {
+	return &Interpreter{
+		globals:   newEnv(nil),
+		functions: make(map[string]ast.FunctionDeclaration),
+		in:        bufio.NewReader(in),
+		out:       out,
+	}
+}
+
+// Run executes program. Runtime errors such as division by zero stop
+// execution and are returned as a diag.Diagnostic. A program that fails
+// leaves the top-level variables and functions as they were before it ran,
+// so that the REPL can discard it along with the types the checker gave
+// them; only changes made inside a list or map stay.
+func (it *Interpreter) Run(program ast.Program) error {
+	vars, functions := maps.Clone(it.globals.vars), maps.Clone(it.functions)
+	// Functions can be called before the line that declares them, as in
+	// the generated Go code.
+	for _, statement := range program.Statements {
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			it.functions[decl.Name] = decl
+		}
+	}
+	err := it.execBlock(program.Statements, it.globals)
+	if err != nil {
+		it.globals.vars, it.functions = vars, functions
+	}
+	return err
+}
+
+// Declare defines a function for later calls, replacing any function of the
+// same name.
+func (it *Interpreter) Declare(decl ast.FunctionDeclaration) {
+	it.functions[decl.Name] = decl
+}
+
+// Lookup returns the value of a top-level variable.
+func (it *Interpreter) Lookup(name string) (Value, bool) {
+	return it.globals.lookup(name)
+}
+
+// runtimeError reports an error in evaluating node, pointing at the whole
+// of it, or at its first character if it runs over several lines.
+func runtimeError(node ast.ASTNode, format string, args ...interface{}) error {
+	span := ast.SpanOf(node)
+	width := 1
+	if span.End.Line == span.Start.Line && span.End.Col > span.Start.Col {
+		width = span.End.Col - span.Start.Col
+	}
+	return diag.Errorf(span.Start.Line, span.Start.Col, width, format, args...)
+}
+
+func (it *Interpreter) execBlock(statements []ast.ASTNode, scope *env) error {
+	for _, stmt := range statements {
+		if err := it.exec(stmt, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (it *Interpreter) exec(statement ast.ASTNode, scope *env) error {
+	switch s := statement.(type) {
+	case ast.ParayuStatement:
+		v, err := it.eval(s.Expression, scope)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(it.out, formatValue(v))
+		return err
+	case ast.KelkStatement:
+		if s.Type == "int" || s.Type == "float64" {
+			n, err := it.readNumber(s)
+			if err != nil {
+				return err
+			}
+			scope.assign(s.Identifier, n)
+			return nil
+		}
+		input, _, err := it.readWord()
+		if err != nil {
+			return err
+		}
+		scope.assign(s.Identifier, input)
+		return nil
+	case ast.AssignmentStatement:
+		v, err := it.eval(s.Expression, scope)
+		if err != nil {
+			return err
+		}
+		scope.assign(s.Identifier, v)
+		return nil
+	case ast.IfStatement:
+		cond, err := it.evalCondition(s.Condition, scope)
+		if err != nil {
+			return err
+		}
+		if cond {
+			return it.execBlock(s.Body, newEnv(scope))
+		}
+		if s.ElseBody != nil {
+			return it.execBlock(s.ElseBody, newEnv(scope))
+		}
+		return nil
+	case ast.WhileStatement:
+		for {
+			cond, err := it.evalCondition(s.Condition, scope)
+			if err != nil {
+				return err
+			}
+			if !cond {
+				return nil
+			}
+			if done, err := it.execLoopBody(s.Label, s.Body, newEnv(scope)); done {
+				return err
+			}
+		}
+	case ast.ForStatement:
+		if s.Iterable != nil {
+			return it.execForEach(s, scope)
+		}
+		return it.execRange(s, scope)
+	case ast.IndexAssignmentStatement:
+		return it.execIndexAssignment(s, scope)
+	case ast.FunctionDeclaration:
+		return nil // Registered by Run
+	case ast.ReturnStatement:
+		if s.Expression == nil {
+			return returnSignal{}
+		}
+		v, err := it.eval(s.Expression, scope)
+		if err != nil {
+			return err
+		}
+		return returnSignal{value: v, hasValue: true}
+	case ast.BreakStatement:
+		return breakSignal{label: s.Label}
+	case ast.ContinueStatement:
+		return continueSignal{label: s.Label}
+	case ast.ExpressionStatement:
+		if call, ok := s.Expression.(ast.CallExpression); ok {
+			_, err := it.call(call, scope)
+			return err
+		}
+		_, err := it.eval(s.Expression, scope)
+		return err
+	default:
+		return runtimeError(statement, "unexpected statement type: %T", statement)
+	}
+}
+
+// readWord reads one line of input and returns its first word, mirroring
+// fmt.Scanln into a single string in the generated code. ended reports that
+// there was no input left to read.
+func (it *Interpreter) readWord() (word string, ended bool, err error) {
+	line, err := it.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", false, err
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", err == io.EOF && line == "", nil
+	}
+	return fields[0], false, nil
+}
+
+// readNumber reads the number kelk(x) ennam or kelk(x) dashamsham asks for,
+// asking again until the input parses, like the malangReadInt and
+// malangReadFloat helpers in the generated code.
+func (it *Interpreter) readNumber(s ast.KelkStatement) (Value, error) {
+	what := "a whole number"
+	if s.Type == "float64" {
+		what = "a number"
+	}
+	for {
+		word, ended, err := it.readWord()
+		if err != nil {
+			return nil, err
+		}
+		if ended {
+			return nil, diag.Errorf(s.Pos.Line, s.Pos.Col, len(s.Identifier), "kelk expected %s, but the input ended", what)
+		}
+		if s.Type == "int" {
+			if n, err := strconv.Atoi(word); err == nil {
+				return n, nil
+			}
+		} else if f, err := strconv.ParseFloat(word, 64); err == nil {
+			return f, nil
+		}
+		if _, err := fmt.Fprintf(it.out, "%q is not %s, try again\n", word, what); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package interp
+
+import (
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+	"github.com/Rohith04MVK/malang/diag"
+)
+
+// Lists are Go slices of values, so sharing and appending behave exactly as
+// they do for the slices in the generated code.
+
+func (it *Interpreter) evalList(e ast.ListLiteral, scope *env) (Value, error) {
+	elementType := strings.TrimPrefix(e.Type, "[]")
+	list := make([]Value, len(e.Elements))
+	for i, element := range e.Elements {
+		v, err := it.eval(element, scope)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = promote(v, elementType)
+	}
+	return list, nil
+}
+
+// evalIndex evaluates the collection and index of an index expression. For
+// a list it checks that the index is in range; for a map it returns the key.
+func (it *Interpreter) evalIndex(e ast.IndexExpression, scope *env) (Value, Value, error) {
+	v, err := it.eval(e.Collection, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch collection := v.(type) {
+	case *Map:
+		k, err := it.eval(e.Index, scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		return collection, collection.key(k), nil
+	case []Value:
+		i, err := it.evalInt(e.Index, scope, "list index")
+		if err != nil {
+			return nil, nil, err
+		}
+		if i < 0 || i >= len(collection) {
+			return nil, nil, diag.Errorf(e.Pos.Line, e.Pos.Col, 1, "index %d out of range for a list of length %d", i, len(collection))
+		}
+		return collection, i, nil
+	default:
+		return nil, nil, runtimeError(e.Collection, "cannot index %s", TypeName(v))
+	}
+}
+
+func (it *Interpreter) evalIndexExpression(e ast.IndexExpression, scope *env) (Value, error) {
+	collection, index, err := it.evalIndex(e, scope)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := collection.(*Map); ok {
+		if v, found := m.lookup(index); found {
+			return v, nil
+		}
+		if m != nil && m.valueType != "" {
+			return zeroValue(m.valueType), nil
+		}
+		return zeroValue(e.Type), nil
+	}
+	return collection.([]Value)[index.(int)], nil
+}
+
+func (it *Interpreter) execIndexAssignment(s ast.IndexAssignmentStatement, scope *env) error {
+	collection, index, err := it.evalIndex(s.Target, scope)
+	if err != nil {
+		return err
+	}
+	v, err := it.eval(s.Value, scope)
+	if err != nil {
+		return err
+	}
+	v = promote(v, s.Target.Type)
+	if m, ok := collection.(*Map); ok {
+		if m == nil {
+			return diag.Errorf(s.Target.Pos.Line, s.Target.Pos.Col, 1, "assignment to entry in a map that was never created")
+		}
+		m.entries[index] = v
+		return nil
+	}
+	collection.([]Value)[index.(int)] = v
+	return nil
+}
+
+// execForEach runs a loop over the elements of a list or the keys of a map,
+// in order. Like Go's range, it sees the list as it was when the loop
+// started; the keys of a map are collected up front, like malangKeys does.
+func (it *Interpreter) execForEach(s ast.ForStatement, scope *env) error {
+	v, err := it.eval(s.Iterable, scope)
+	if err != nil {
+		return err
+	}
+	var list []Value
+	switch collection := v.(type) {
+	case []Value:
+		list = collection
+	case *Map:
+		list = collection.sortedKeys()
+	default:
+		return runtimeError(s.Iterable, "cannot loop over %s", TypeName(v))
+	}
+	loopScope := newEnv(scope)
+	for _, element := range list {
+		loopScope.define(s.Identifier, element)
+		if done, err := it.execLoopBody(s.Label, s.Body, newEnv(loopScope)); done {
+			return err
+		}
+	}
+	return nil
+}
+
+// callBuiltin runs one of malang's builtin functions, or returns false if
+// call is not one.
+func (it *Interpreter) callBuiltin(call ast.CallExpression, scope *env) (Value, bool, error) {
+	arity, ok := check.Arity(call.Function)
+	if !ok {
+		return nil, false, nil
+	}
+	if len(call.Arguments) != arity {
+		return nil, true, runtimeError(call, "function '%s' takes %d arguments, got %d", call.Function, arity, len(call.Arguments))
+	}
+	args := make([]Value, len(call.Arguments))
+	for i, argument := range call.Arguments {
+		v, err := it.eval(argument, scope)
+		if err != nil {
+			return nil, true, err
+		}
+		args[i] = v
+	}
+	if m, ok := args[0].(*Map); ok {
+		switch call.Function {
+		case "neelam":
+			return m.len(), true, nil
+		case "undo":
+			_, found := m.lookup(m.key(args[1]))
+			return found, true, nil
+		case "kalay":
+			if m != nil {
+				delete(m.entries, m.key(args[1]))
+			}
+			return nil, true, nil
+		}
+	}
+	list, ok := args[0].([]Value)
+	if !ok || call.Function == "undo" || call.Function == "kalay" {
+		return nil, true, runtimeError(call.Arguments[0], "%s cannot be used on %s", call.Function, TypeName(args[0]))
+	}
+
+	if call.Function == "neelam" {
+		return len(list), true, nil
+	}
+	// Capping the capacity makes append copy, so lists made by two cherkk
+	// calls on the same list never share their elements.
+	return append(list[:len(list):len(list)], promote(args[1], strings.TrimPrefix(call.Type, "[]"))), true, nil
+}
@@ -0,0 +1,87 @@
+package interp
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
+)
+
+// breakSignal and continueSignal unwind execution from a nirthu or thudaru
+// back to the loop they belong to, the same way returnSignal does for
+// thirich_kodukk. An empty label means the innermost loop.
+type breakSignal struct{ label string }
+
+type continueSignal struct{ label string }
+
+func (breakSignal) Error() string    { return "nirthu outside of a loop" }
+func (continueSignal) Error() string { return "thudaru outside of a loop" }
+
+// execLoopBody runs one round of a loop labelled label. done reports that
+// the loop should stop, either because of a nirthu aimed at it or because of
+// an error, which includes signals aimed at a loop further out.
+func (it *Interpreter) execLoopBody(label string, body []ast.ASTNode, scope *env) (done bool, err error) {
+	err = it.execBlock(body, scope)
+	switch signal := err.(type) {
+	case breakSignal:
+		if signal.label == "" || signal.label == label {
+			return true, nil
+		}
+	case continueSignal:
+		if signal.label == "" || signal.label == label {
+			return false, nil
+		}
+	}
+	return err != nil, err
+}
+
+// execRange runs a loop over a range of integers. The bounds and the step
+// are worked out once, before the first round. A positive step counts up
+// until i passes End and a negative one counts down, so a range whose start
+// is already past its end runs no rounds at all. As in the Go for loop
+// codegen emits, assigning to the loop variable in the body changes where
+// the count carries on from.
+func (it *Interpreter) execRange(s ast.ForStatement, scope *env) error {
+	start, err := it.evalInt(s.Start, scope, "range start")
+	if err != nil {
+		return err
+	}
+	end, err := it.evalInt(s.End, scope, "range end")
+	if err != nil {
+		return err
+	}
+	step := 1
+	if s.Step != nil {
+		if step, err = it.evalInt(s.Step, scope, "range step"); err != nil {
+			return err
+		}
+		if step == 0 {
+			return diag.Errorf(s.Pos.Line, s.Pos.Col, len(s.Identifier), "the step of a range cannot be 0")
+		}
+	}
+
+	loopScope := newEnv(scope)
+	for i := start; inRange(i, end, step, s.Exclusive); i += step {
+		loopScope.define(s.Identifier, i)
+		if done, err := it.execLoopBody(s.Label, s.Body, newEnv(loopScope)); done {
+			return err
+		}
+		if n, ok := loopScope.vars[s.Identifier].(int); ok {
+			i = n
+		}
+	}
+	return nil
+}
+
+// inRange reports whether a range loop counting towards end by step goes on
+// for another round with i.
+func inRange(i, end, step int, exclusive bool) bool {
+	switch {
+	case step > 0 && exclusive:
+		return i < end
+	case step > 0:
+		return i <= end
+	case exclusive:
+		return i > end
+	default:
+		return i >= end
+	}
+}
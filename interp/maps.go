@@ -0,0 +1,116 @@
+package interp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+)
+
+// Map is a malang map. Like a Go map it is shared, not copied, when it is
+// assigned or passed to a function. The key type is kept so that an int used
+// as the key of a map with float keys finds the same entry as in Go, and the
+// value type so that a missing key gives the right zero value.
+type Map struct {
+	keyType   string
+	valueType string
+	entries   map[Value]Value
+}
+
+func newMap(mapType string) *Map {
+	keyType, valueType := splitMapType(mapType)
+	return &Map{keyType: keyType, valueType: valueType, entries: make(map[Value]Value)}
+}
+
+// splitMapType returns the key and value types of a map type such as
+// "map[string]int", as annotated by the type checker.
+func splitMapType(mapType string) (key, value string) {
+	rest := strings.TrimPrefix(mapType, "map[")
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return "", ""
+	}
+	return rest[:end], rest[end+1:]
+}
+
+func (m *Map) key(k Value) Value {
+	if m == nil {
+		return k
+	}
+	return promote(k, m.keyType)
+}
+
+// lookup and len treat a nil map as empty, as Go does.
+func (m *Map) lookup(k Value) (Value, bool) {
+	if m == nil {
+		return nil, false
+	}
+	v, ok := m.entries[k]
+	return v, ok
+}
+
+func (m *Map) len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.entries)
+}
+
+// sortedKeys returns the keys of m in order, matching the malangKeys helper
+// the code generator emits.
+func (m *Map) sortedKeys() []Value {
+	keys := make([]Value, 0, m.len())
+	if m != nil {
+		for k := range m.entries {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		switch a := keys[i].(type) {
+		case string:
+			return a < keys[j].(string)
+		case float64:
+			return a < keys[j].(float64)
+		default:
+			return a.(int) < keys[j].(int)
+		}
+	})
+	return keys
+}
+
+func (it *Interpreter) evalMap(e ast.MapLiteral, scope *env) (Value, error) {
+	m := newMap(e.Type)
+	for i := range e.Keys {
+		k, err := it.eval(e.Keys[i], scope)
+		if err != nil {
+			return nil, err
+		}
+		v, err := it.eval(e.Values[i], scope)
+		if err != nil {
+			return nil, err
+		}
+		m.entries[m.key(k)] = promote(v, m.valueType)
+	}
+	return m, nil
+}
+
+// zeroValue is the value a Go program gets for a key a map does not have,
+// and a function gets back from one that ends without returning.
+func zeroValue(goType string) Value {
+	switch {
+	case goType == "int":
+		return 0
+	case goType == "float64":
+		return 0.0
+	case goType == "string":
+		return ""
+	case goType == "bool":
+		return false
+	case strings.HasPrefix(goType, "[]"):
+		return []Value(nil)
+	case strings.HasPrefix(goType, "map["):
+		return (*Map)(nil)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package interp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Value is a malang runtime value. Integers are Go ints, floats are Go
+// float64s, strings are Go strings and booleans are Go bools.
+type Value interface{}
+
+// TypeName returns the malang type of v, using the same names as the code
+// generator's type inference.
+func TypeName(v Value) string {
+	switch v.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "float64"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []Value:
+		return "list"
+	case *Map:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// formatValue renders v the way the generated Go program prints it.
+func formatValue(v Value) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return formatFloat(v)
+	case string:
+		return v
+	case bool:
+		if v {
+			return "sheri"
+		}
+		return "thettu"
+	case []Value:
+		elements := make([]string, len(v))
+		for i, element := range v {
+			elements[i] = formatValue(element)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *Map:
+		keys := v.sortedKeys()
+		entries := make([]string, len(keys))
+		for i, k := range keys {
+			entries[i] = formatValue(k) + ": " + formatValue(v.entries[k])
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatFloat prints a float in plain decimal, always with a fractional part
+// so it reads as a float, and switches to an exponent only for very large or
+// very small magnitudes. It matches the malangFloat helper codegen emits.
+func formatFloat(f float64) string {
+	if abs := math.Abs(f); math.IsInf(f, 0) || math.IsNaN(f) || (abs != 0 && (abs < 1e-4 || abs >= 1e21)) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// promote converts v to the static type target where the language allows an
+// implicit conversion, which is only int to float.
+func promote(v Value, target string) Value {
+	if n, ok := v.(int); ok && target == "float64" {
+		return float64(n)
+	}
+	return v
+}
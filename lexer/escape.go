@@ -0,0 +1,57 @@
+package lexer
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// escapes maps the character after a backslash to what the escape stands for.
+var escapes = map[byte]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'"':  '"',
+	'\\': '\\',
+	'{':  '{',
+	'}':  '}',
+}
+
+const escapeHint = `write \\ for a backslash; the other escapes are \n, \t, \r, \", \{, \} and \u{...}`
+
+// escape decodes the escape sequence at the start of input, which begins
+// with a backslash. It returns the character the sequence stands for and its
+// length in bytes, or a message saying what is wrong with it.
+func escape(input string) (r rune, length int, problem string) {
+	if len(input) < 2 {
+		return 0, len(input), "unterminated escape sequence"
+	}
+	if r, ok := escapes[input[1]]; ok {
+		return r, 2, ""
+	}
+	if input[1] != 'u' {
+		r, size := utf8.DecodeRuneInString(input[1:])
+		return 0, 1 + size, fmt.Sprintf("unknown escape sequence: %q after a backslash", r)
+	}
+
+	// \u{1F600}: one to six hex digits naming a unicode character.
+	if len(input) < 3 || input[2] != '{' {
+		return 0, 2, `\u must be followed by the character's number in hex, as in \u{e9}`
+	}
+	i := 3
+	for i < len(input) && i < 3+6 && isHexDigit(input[i]) {
+		i++
+	}
+	if i == 3 || i == len(input) || input[i] != '}' {
+		return 0, i, `invalid unicode escape: expected one to six hex digits and a '}'`
+	}
+	code, _ := strconv.ParseUint(input[3:i], 16, 32)
+	if code > utf8.MaxRune || (code >= 0xD800 && code <= 0xDFFF) {
+		return 0, i + 1, fmt.Sprintf("\\u{%s} is not a valid unicode character", input[3:i])
+	}
+	return rune(code), i + 1, ""
+}
+
+func isHexDigit(char byte) bool {
+	return IsDigit(char) || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
@@ -15,3 +15,23 @@ func IsDigit(char byte) bool {
 func IsAlpha(char byte) bool {
 	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
 }
+
+// exponentLength returns the length of the exponent (e10, E-3, e+6) at the
+// start of input, or 0 if there is none.
+func exponentLength(input string) int {
+	if len(input) < 2 || (input[0] != 'e' && input[0] != 'E') {
+		return 0
+	}
+	i := 1
+	if input[i] == '+' || input[i] == '-' {
+		i++
+	}
+	digits := i
+	for i < len(input) && IsDigit(input[i]) {
+		i++
+	}
+	if i == digits {
+		return 0
+	}
+	return i
+}
@@ -1,33 +1,116 @@
 package lexer
 
 import (
-	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/Rohith04MVK/malang/diag"
 )
 
+// Lex tokenizes input and panics on the first lexical error. Use LexDiag to
+// get every problem back as diagnostics instead.
 func Lex(input string) []Token {
+	tokens, _, err := LexDiag(input)
+	if err != nil {
+		panic(err)
+	}
+	return tokens
+}
+
+// LexDiag tokenizes input, collecting lexical errors as diagnostics rather than
+// stopping at the first one. The returned error is non-nil if any error was
+// reported; the tokens are still usable for best-effort tooling.
+func LexDiag(input string) ([]Token, []diag.Diagnostic, error) {
 	tokens := []Token{}
+	var diags []diag.Diagnostic
 	line := 1
 	col := 1
 
-	keywords := map[string]string{
-		"parayu":          TokParayu,
-		"kelk":            TokKelk,
-		"ith_sheriyano":   TokAadhyamayi,
-		"enkil":           TokAthengil,
-		"alle":            TokIlla,
-		"ellam_sheriyano": TokEllamSheriyano,
-		"oron_ayi":        TokOnninuMumbu,
-		"edukk":           TokEdukk,
+	// The keywords are the kinds from TokParayu to TokThettu.
+	keywords := make(map[string]Kind)
+	for kind := TokParayu; kind <= TokThettu; kind++ {
+		keywords[kind.String()] = kind
 	}
 
-	operators := []string{"==", "!=", "<=", ">=", "<", ">", "=", "+", "-", "*", "/"} // Add missing operators and keep longer operators first
+	i := 0
+	var startLine, startCol, start int
+	var comments []Comment // Comments waiting for the next token
+	// emit adds a token that runs from the start of the current one up to
+	// where the lexer has got to.
+	emit := func(kind Kind, value string) {
+		tokens = append(tokens, Token{
+			Kind: kind, Value: value, Text: input[start:i], Line: startLine, Col: startCol, Offset: start,
+			EndLine: line, EndCol: col, EndOffset: i, Comments: comments,
+		})
+		comments = nil
+	}
+
+	// interpolations are the strings whose braces the lexer is inside, from
+	// the outermost in. depth counts the braces opened since, so that the
+	// '}' which ends the expression can be told apart from one that closes
+	// a map literal inside it.
+	type interpolation struct{ line, col, depth int }
+	var interpolations []interpolation
+
+	// lexString lexes the text of a string literal up to its closing quote,
+	// which makes a token of kind whole, or up to a '{', which makes one of
+	// kind opening and leaves the lexer inside the braces. quoteLine and
+	// quoteCol are where the string's opening quote is.
+	lexString := func(whole, opening Kind, quoteLine, quoteCol int) {
+		var value strings.Builder
+		for i < len(input) && input[i] != '"' && input[i] != '{' {
+			if input[i] == '\\' {
+				r, size, problem := escape(input[i:])
+				if problem != "" {
+					diags = append(diags, diag.Errorf(line, col, utf8.RuneCountInString(input[i:i+size]), "%s", problem).
+						WithHint(escapeHint))
+				} else {
+					value.WriteRune(r)
+				}
+				for _, r := range input[i : i+size] {
+					if r == '\n' { // A backslash at the end of a line
+						line++
+						col = 1
+					} else {
+						col++
+					}
+				}
+				i += size
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(input[i:])
+			if r == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+			value.WriteString(input[i : i+size])
+			i += size
+		}
+		if i == len(input) {
+			diags = append(diags, diag.Errorf(quoteLine, quoteCol, 1, "unterminated string literal").
+				WithHint("add a closing '\"' to end the string"))
+			emit(whole, value.String())
+			tokens[len(tokens)-1].Unterminated = true
+			return
+		}
+		i++
+		col++
+		if input[i-1] == '"' {
+			emit(whole, value.String())
+			return
+		}
+		emit(opening, value.String())
+		interpolations = append(interpolations, interpolation{line: quoteLine, col: quoteCol})
+	}
 
-	for i := 0; i < len(input); {
+	for i < len(input) {
 		char := input[i]
 
-		// Skip whitespace
-		if char == ' ' || char == '\t' {
+		// Skip whitespace, including the carriage return of a Windows
+		// line ending.
+		if IsWhitespace(rune(char)) {
 			i++
 			col++
 			continue
@@ -39,120 +122,168 @@ func Lex(input string) []Token {
 			i++
 			continue
 		}
+		startLine, startCol, start = line, col, i
 
-		// String literals
-		if char == '"' {
-			start := i + 1
-			for i++; i < len(input) && input[i] != '"'; i++ {
-				if input[i] == '\n' {
+		// Block comments run to the next */ and may span lines.
+		if strings.HasPrefix(input[i:], "/*") {
+			end := strings.Index(input[i+2:], "*/")
+			if end < 0 {
+				diags = append(diags, diag.Errorf(line, col, 2, "unterminated comment").
+					WithHint("add '*/' to end the comment"))
+				end = len(input) - i
+			} else {
+				end += 4
+			}
+			text := input[i : i+end]
+			comments = append(comments, Comment{Text: text, Line: line, Col: col, Offset: i})
+			for _, r := range text {
+				if r == '\n' {
 					line++
 					col = 1
 				} else {
 					col++
 				}
 			}
-			if i < len(input) && input[i] == '"' {
-				tokens = append(tokens, Token{Type: TokString, Value: input[start:i], Line: line, Col: col})
+			i += end
+			continue
+		}
+
+		// Line comments run to the end of the line.
+		if strings.HasPrefix(input[i:], "//") {
+			end := strings.IndexByte(input[i:], '\n')
+			if end < 0 {
+				end = len(input) - i
+			}
+			text := strings.TrimRight(input[i:i+end], "\r")
+			comments = append(comments, Comment{Text: text, Line: line, Col: col, Offset: i})
+			i += len(text)
+			col += utf8.RuneCountInString(text)
+			continue
+		}
+
+		// String literals. A '{' in one starts an expression to be
+		// interpolated, and the string carries on after the matching '}'.
+		if char == '"' {
+			i++
+			col++
+			lexString(TokString, TokStringStart, startLine, startCol)
+			continue
+		}
+
+		if len(interpolations) > 0 && (char == '{' || char == '}') {
+			top := &interpolations[len(interpolations)-1]
+			switch {
+			case char == '{':
+				top.depth++ // A map literal or block inside the braces
+			case top.depth > 0:
+				top.depth--
+			default:
+				open := *top
+				interpolations = interpolations[:len(interpolations)-1]
 				i++
-				// Remove the duplicate column increment - it's already handled in the loop
-				// col += i - start + 2  <- Remove this line
-			} else {
-				panic(fmt.Sprintf("Unterminated string literal at line %d, col %d", line, col))
+				col++
+				lexString(TokStringEnd, TokStringMiddle, open.line, open.col)
+				continue
 			}
+		}
 
+		// Raw strings: no escapes, and they may run over several lines.
+		if char == '`' {
+			end := strings.IndexByte(input[i+1:], '`')
+			if end < 0 {
+				diags = append(diags, diag.Errorf(startLine, startCol, 1, "unterminated raw string literal").
+					WithHint("add a closing '`' to end the string"))
+				end = len(input) - i - 1
+			}
+			text := input[i+1 : i+1+end]
+			for _, r := range input[i : i+1+end] {
+				if r == '\n' {
+					line++
+					col = 1
+				} else {
+					col++
+				}
+			}
+			i += 1 + end
+			terminated := i < len(input)
+			if terminated {
+				i++
+				col++
+			}
+			// As in Go, carriage returns are dropped so a file with
+			// Windows line endings means the same thing.
+			emit(TokString, strings.ReplaceAll(text, "\r", ""))
+			tokens[len(tokens)-1].Unterminated = !terminated
 			continue
 		}
 
 		// Identifiers and Keywords
 		if IsAlpha(char) {
-			start := i
 			for i < len(input) && (IsAlpha(input[i]) || IsDigit(input[i]) || input[i] == '_') {
 				i++
 			}
+			col += i - start
 			value := input[start:i]
-			if tokenType, ok := keywords[value]; ok {
-				tokens = append(tokens, Token{Type: tokenType, Value: value, Line: line, Col: col})
+			if kind, ok := keywords[value]; ok {
+				emit(kind, value)
 			} else {
-				tokens = append(tokens, Token{Type: TokIdentifier, Value: value, Line: line, Col: col})
+				emit(TokIdentifier, value)
 			}
-			col += i - start
 			continue
 		}
 
-		// Numbers
+		// Numbers: 42, 3.14, 6.02e23, 1e-9
 		if IsDigit(char) {
-			start := i
+			kind := TokInteger
 			for i < len(input) && IsDigit(input[i]) {
 				i++
 			}
-			tokens = append(tokens, Token{Type: TokInteger, Value: input[start:i], Line: line, Col: col})
+			// A '.' only starts a fraction if a digit follows, so 1..5 stays a range.
+			if i+1 < len(input) && input[i] == '.' && IsDigit(input[i+1]) {
+				kind = TokFloat
+				for i++; i < len(input) && IsDigit(input[i]); i++ {
+				}
+			}
+			if exponent := exponentLength(input[i:]); exponent > 0 {
+				kind = TokFloat
+				i += exponent
+			}
 			col += i - start
+			emit(kind, input[start:i])
 			continue
 		}
 
-		// Operators
-		matchedOperator := false
-		for _, op := range operators {
-			if strings.HasPrefix(input[i:], op) {
-				// Use specific token types based on the operator
-				tokenType := TokOperator
-				switch op {
-				case "+":
-					tokenType = TokOperator
-				case "-":
-					tokenType = TokMinus
-				case "*":
-					tokenType = TokMultiply
-				case "/":
-					tokenType = TokDivide
-				}
-				tokens = append(tokens, Token{Type: tokenType, Value: op, Line: line, Col: col})
-				i += len(op)
-				col += len(op)
-				matchedOperator = true
+		// Operators and punctuation
+		matched := false
+		for _, kind := range symbols {
+			if symbol := kind.String(); strings.HasPrefix(input[i:], symbol) {
+				i += len(symbol)
+				col += len(symbol)
+				emit(kind, symbol)
+				matched = true
 				break
 			}
 		}
-		if matchedOperator {
+		if matched {
 			continue
 		}
 
-		// Range ..
-		if i+1 < len(input) && input[i] == '.' && input[i+1] == '.' {
-			tokens = append(tokens, Token{Type: TokRange, Value: "..", Line: line, Col: col})
-			i += 2
-			col += 2
-			continue
-		}
-
-		// Parentheses and Braces, Comma
-		switch char {
-		case '(':
-			tokens = append(tokens, Token{Type: TokLParen, Value: "(", Line: line, Col: col})
-			i++
-			col++
-		case ')':
-			tokens = append(tokens, Token{Type: TokRParen, Value: ")", Line: line, Col: col})
-			i++
-			col++
-		case '{':
-			tokens = append(tokens, Token{Type: TokLBrace, Value: "{", Line: line, Col: col})
-			i++
-			col++
-		case '}':
-			tokens = append(tokens, Token{Type: TokRBrace, Value: "}", Line: line, Col: col})
-			i++
-			col++
-		case ',':
-			tokens = append(tokens, Token{Type: TokComma, Value: ",", Line: line, Col: col})
-			i++
-			col++
-
-		default:
-			panic(fmt.Sprintf("Unexpected character '%c' at line %d, col %d", char, line, col))
-		}
+		r, size := utf8.DecodeRuneInString(input[i:])
+		diags = append(diags, diag.Errorf(line, col, 1, "unexpected character %q", r))
+		i += size
+		col++
 	}
 
-	tokens = append(tokens, Token{Type: TokEOF, Value: "", Line: line, Col: col})
-	return tokens
+	startLine, startCol, start = line, col, i
+	// Close the strings the input ended inside the braces of, innermost
+	// first, so the parser sees whole expressions.
+	for k := len(interpolations) - 1; k >= 0; k-- {
+		open := interpolations[k]
+		diags = append(diags, diag.Errorf(open.line, open.col, 1, "unterminated string interpolation").
+			WithHint("close the expression with '}' and the string with '\"'"))
+		emit(TokStringEnd, "")
+		tokens[len(tokens)-1].Unterminated = true
+	}
+	emit(TokEOF, "")
+	return tokens, diags, diag.Err(diags)
 }
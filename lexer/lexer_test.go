@@ -0,0 +1,32 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rohith04MVK/malang/lexer"
+)
+
+// A file with Windows line endings gives the same tokens, at the same lines
+// and columns, as one with Unix line endings.
+func TestWindowsLineEndings(t *testing.T) {
+	source := "x = 1 // one\nparayu(x)\n/* a\ncomment */ s = `raw\ntext`\n"
+	unix, diags, err := lexer.LexDiag(source)
+	if err != nil || len(diags) > 0 {
+		t.Fatalf("LexDiag: %v %v", err, diags)
+	}
+	windows, diags, err := lexer.LexDiag(strings.ReplaceAll(source, "\n", "\r\n"))
+	if err != nil || len(diags) > 0 {
+		t.Fatalf("LexDiag with \\r\\n: %v %v", err, diags)
+	}
+	if len(windows) != len(unix) {
+		t.Fatalf("got %d tokens, want %d", len(windows), len(unix))
+	}
+	for i, want := range unix {
+		got := windows[i]
+		if got.Kind != want.Kind || got.Value != want.Value || got.Line != want.Line || got.Col != want.Col {
+			t.Errorf("token %d: got %v %q at %d:%d, want %v %q at %d:%d",
+				i, got.Kind, got.Value, got.Line, got.Col, want.Kind, want.Value, want.Line, want.Col)
+		}
+	}
+}
@@ -1,34 +1,236 @@
 package lexer
 
+import "fmt"
+
 type Token struct {
-	Type  string
-	Value string
-	Line  int
-	Col   int
+	Kind   Kind
+	Value  string
+	Text   string // The token as written; for a string literal, Value is what it stands for
+	Line   int
+	Col    int
+	Offset int // Byte offset of the first character
+
+	// Where the token ends: just past its last character.
+	EndLine   int
+	EndCol    int
+	EndOffset int
+
+	Comments []Comment // Comments between the previous token and this one
+
+	// Unterminated marks a string the input ended inside of. The lexer
+	// reports it and ends the string there, so the parser need not report
+	// the missing tokens after it too.
+	Unterminated bool
 }
 
-// Constants for token types
+// Comment is a comment in the source. Comments are not tokens the parser
+// sees: each one rides along on the token after it, so tools that print the
+// source back, such as the formatter, can keep them.
+type Comment struct {
+	Text   string // The whole comment, including the // or /* and */
+	Line   int
+	Col    int
+	Offset int
+}
+
+// Kind is the kind of a token. Every keyword, operator and punctuation mark
+// has a kind of its own, so the parser never needs to look at a token's
+// Value to tell them apart.
+type Kind int
+
 const (
-	TokParayu         = "PARAYU"
-	TokKelk           = "KELK"
-	TokAadhyamayi     = "AADHYAMAYI"
-	TokAthengil       = "ATHENGIL"
-	TokIlla           = "ILLA"
-	TokEllamSheriyano = "ELLAM_SHERIYANO"
-	TokOnninuMumbu    = "ONNINU_MUMBU"
-	TokEdukk          = "EDUKK"
-	TokString         = "STRING"
-	TokIdentifier     = "IDENTIFIER"
-	TokInteger        = "INTEGER"
-	TokOperator       = "OPERATOR"
-	TokLParen         = "LPAREN"
-	TokRParen         = "RPAREN"
-	TokLBrace         = "LBRACE"
-	TokRBrace         = "RBRACE"
-	TokRange          = "RANGE"
-	TokComma          = "COMMA"
-	TokEOF            = "EOF"
-	TokMinus          = "MINUS"
-	TokMultiply       = "MULTIPLY"
-	TokDivide         = "DIVIDE"
+	TokEOF Kind = iota
+
+	// Literals and names, whose Value varies
+	TokIdentifier
+	TokString
+	TokInteger
+	TokFloat
+
+	// An interpolated string such as "a{x}b{y}c" is lexed as TokStringStart
+	// ("a"), the tokens of x, TokStringMiddle ("b"), the tokens of y and
+	// TokStringEnd ("c"). Their Value is the text between the braces.
+	TokStringStart
+	TokStringMiddle
+	TokStringEnd
+
+	// Keywords
+	TokParayu
+	TokKelk
+	TokAadhyamayi
+	TokAthengil
+	TokIlla
+	TokEllamSheriyano
+	TokOnninuMumbu
+	TokEdukk
+	TokPani
+	TokThirichKodukk
+	TokNirthu
+	TokThudaru
+	TokSheri
+	TokThettu
+
+	// Operators
+	TokPlus
+	TokMinus
+	TokMultiply
+	TokDivide
+	TokModulo
+	TokPower
+	TokEqual
+	TokNotEqual
+	TokLess
+	TokLessEqual
+	TokGreater
+	TokGreaterEqual
+	TokAnd
+	TokOr
+	TokNot
+	TokAssign
+	TokPlusAssign
+	TokMinusAssign
+	TokMultiplyAssign
+	TokDivideAssign
+	TokModuloAssign
+	TokPowerAssign
+
+	// Punctuation
+	TokLParen
+	TokRParen
+	TokLBrace
+	TokRBrace
+	TokLBracket
+	TokRBracket
+	TokRange
+	TokRangeExclusive
+	TokComma
+	TokColon
 )
+
+// String returns how a token of kind k is spelled in the source, or, for the
+// kinds whose spelling varies, what it is.
+func (k Kind) String() string {
+	switch k {
+	case TokEOF:
+		return "end of file"
+	case TokIdentifier:
+		return "identifier"
+	case TokString:
+		return "string"
+	case TokInteger:
+		return "integer"
+	case TokFloat:
+		return "float"
+	case TokStringStart:
+		return "start of a string"
+	case TokStringMiddle:
+		return "part of a string"
+	case TokStringEnd:
+		return "end of a string"
+	case TokParayu:
+		return "parayu"
+	case TokKelk:
+		return "kelk"
+	case TokAadhyamayi:
+		return "ith_sheriyano"
+	case TokAthengil:
+		return "enkil"
+	case TokIlla:
+		return "alle"
+	case TokEllamSheriyano:
+		return "ellam_sheriyano"
+	case TokOnninuMumbu:
+		return "oron_ayi"
+	case TokEdukk:
+		return "edukk"
+	case TokPani:
+		return "pani"
+	case TokThirichKodukk:
+		return "thirich_kodukk"
+	case TokNirthu:
+		return "nirthu"
+	case TokThudaru:
+		return "thudaru"
+	case TokSheri:
+		return "sheri"
+	case TokThettu:
+		return "thettu"
+	case TokPlus:
+		return "+"
+	case TokMinus:
+		return "-"
+	case TokMultiply:
+		return "*"
+	case TokDivide:
+		return "/"
+	case TokModulo:
+		return "%"
+	case TokPower:
+		return "**"
+	case TokEqual:
+		return "=="
+	case TokNotEqual:
+		return "!="
+	case TokLess:
+		return "<"
+	case TokLessEqual:
+		return "<="
+	case TokGreater:
+		return ">"
+	case TokGreaterEqual:
+		return ">="
+	case TokAnd:
+		return "&&"
+	case TokOr:
+		return "||"
+	case TokNot:
+		return "!"
+	case TokAssign:
+		return "="
+	case TokPlusAssign:
+		return "+="
+	case TokMinusAssign:
+		return "-="
+	case TokMultiplyAssign:
+		return "*="
+	case TokDivideAssign:
+		return "/="
+	case TokModuloAssign:
+		return "%="
+	case TokPowerAssign:
+		return "**="
+	case TokLParen:
+		return "("
+	case TokRParen:
+		return ")"
+	case TokLBrace:
+		return "{"
+	case TokRBrace:
+		return "}"
+	case TokLBracket:
+		return "["
+	case TokRBracket:
+		return "]"
+	case TokRange:
+		return ".."
+	case TokRangeExclusive:
+		return "..<"
+	case TokComma:
+		return ","
+	case TokColon:
+		return ":"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// symbols are the operators and punctuation, longest first so that the
+// lexer matches "**=" before "**" before "*".
+var symbols = []Kind{
+	TokPowerAssign, TokRangeExclusive,
+	TokEqual, TokNotEqual, TokLessEqual, TokGreaterEqual, TokAnd, TokOr,
+	TokPlusAssign, TokMinusAssign, TokMultiplyAssign, TokDivideAssign, TokModuloAssign,
+	TokPower, TokRange,
+	TokLess, TokGreater, TokAssign, TokNot, TokPlus, TokMinus, TokMultiply, TokDivide, TokModulo,
+	TokLParen, TokRParen, TokLBrace, TokRBrace, TokLBracket, TokRBracket, TokComma, TokColon,
+}
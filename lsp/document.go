@@ -0,0 +1,126 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+	"github.com/Rohith04MVK/malang/diag"
+	"github.com/Rohith04MVK/malang/lexer"
+	"github.com/Rohith04MVK/malang/parser"
+)
+
+// document is an open .malang file and what the compiler made of it.
+type document struct {
+	uri   string
+	lines []string
+	diags []diag.Diagnostic
+	index *index
+}
+
+// analyze runs the same phases as main.go on text: lexing and parsing, and
+// type checking if those went through. The program is indexed either way, so
+// hover and go-to-definition keep working while a line is half typed.
+func analyze(uri, text string) *document {
+	doc := &document{uri: uri, lines: strings.Split(text, "\n")}
+	tokens, lexDiags, lexErr := lexer.LexDiag(text)
+	program, parseDiags, parseErr := parser.NewParser(tokens).ParseDiag()
+	if lexErr != nil || parseErr != nil {
+		doc.diags = append(lexDiags, parseDiags...)
+	} else {
+		var checked ast.Program
+		checked, doc.diags, _ = check.Check(program)
+		program = checked
+	}
+	doc.index = buildIndex(program)
+	return doc
+}
+
+// diagnostics converts the document's diagnostics for the client.
+func (doc *document) diagnostics() []Diagnostic {
+	result := []Diagnostic{}
+	for _, d := range doc.diags {
+		line, col := d.Line, d.Col
+		if line == 0 {
+			line, col = 1, 1 // No position: point at the start of the file
+		}
+		span := d.Span
+		if span < 1 {
+			span = 1
+		}
+		message := d.Message
+		if d.Hint != "" {
+			message += "\nhint: " + d.Hint
+		}
+		severity := SeverityError
+		switch d.Severity {
+		case diag.Warning:
+			severity = SeverityWarning
+		case diag.Note:
+			severity = SeverityInformation
+		}
+		result = append(result, Diagnostic{
+			Range:    doc.rangeOf(line, col, col+span),
+			Severity: severity,
+			Source:   "malang",
+			Message:  message,
+		})
+	}
+	return result
+}
+
+// position converts a 1-based line and rune column into an LSP position.
+func (doc *document) position(line, col int) Position {
+	text := ""
+	if line >= 1 && line <= len(doc.lines) {
+		text = doc.lines[line-1]
+	}
+	character := 0
+	for _, r := range text {
+		if col <= 1 {
+			break
+		}
+		character += utf16Len(r)
+		col--
+	}
+	return Position{Line: line - 1, Character: character + col - 1}
+}
+
+// source converts an LSP position into a 1-based line and rune column.
+func (doc *document) source(pos Position) (line, col int) {
+	line, col = pos.Line+1, 1
+	if pos.Line < 0 || pos.Line >= len(doc.lines) {
+		return line, col
+	}
+	character := pos.Character
+	for _, r := range doc.lines[pos.Line] {
+		if character <= 0 {
+			break
+		}
+		character -= utf16Len(r)
+		col++
+	}
+	return line, col
+}
+
+// rangeOf is the range of columns [col, endCol) on a line.
+func (doc *document) rangeOf(line, col, endCol int) Range {
+	return Range{Start: doc.position(line, col), End: doc.position(line, endCol)}
+}
+
+func (doc *document) spanRange(span ast.Span) Range {
+	return Range{Start: doc.position(span.Start.Line, span.Start.Col), End: doc.position(span.End.Line, span.End.Col)}
+}
+
+// nameRange is the range of a name written at pos.
+func (doc *document) nameRange(pos ast.Pos, name string) Range {
+	return doc.rangeOf(pos.Line, pos.Col, pos.Col+utf8.RuneCountInString(name))
+}
+
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
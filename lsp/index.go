@@ -0,0 +1,279 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+)
+
+// symbol is a variable, parameter or function of a document.
+type symbol struct {
+	name     string
+	function *ast.FunctionDeclaration // nil for variables and parameters
+	builtin  bool
+	def      ast.Pos  // Where the name is first written; zero for builtins
+	decl     ast.Span // Statement that defines it
+	typ      string   // Type of a variable, as far as it is known
+}
+
+// occurrence is a place where a symbol's name is written.
+type occurrence struct {
+	line, col, endCol int // 1-based, in runes; endCol is just past the name
+	sym               *symbol
+	typ               string // Type the checker gave this use, if any
+}
+
+// index records, for every name written in a program, the symbol it refers
+// to. Names are resolved with the type checker's scope rules: a variable
+// lives in the block where it is first assigned, and a function sees only
+// its own parameters and variables.
+type index struct {
+	occurrences []occurrence
+	symbols     []*symbol // Every symbol, in the order it was defined
+	topLevel    []*symbol // Functions and top-level variables, for the outline
+
+	functions map[string]*symbol
+	builtins  map[string]*symbol
+}
+
+// scope is one level of the name scope chain.
+type scope struct {
+	names  map[string]*symbol
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{names: make(map[string]*symbol), parent: parent}
+}
+
+func (s *scope) lookup(name string) *symbol {
+	for sc := s; sc != nil; sc = sc.parent {
+		if sym, ok := sc.names[name]; ok {
+			return sym
+		}
+	}
+	return nil
+}
+
+func buildIndex(program ast.Program) *index {
+	idx := &index{functions: make(map[string]*symbol), builtins: make(map[string]*symbol)}
+	for _, name := range check.Builtins() {
+		idx.builtins[name] = &symbol{name: name, builtin: true}
+	}
+	for _, statement := range program.Statements {
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			if _, seen := idx.functions[decl.Name]; seen {
+				continue
+			}
+			sym := &symbol{name: decl.Name, function: &decl, def: decl.Pos, decl: decl.Span}
+			idx.functions[decl.Name] = sym
+			idx.symbols = append(idx.symbols, sym)
+		}
+	}
+
+	globals := newScope(nil)
+	for _, statement := range program.Statements {
+		if decl, ok := statement.(ast.FunctionDeclaration); ok {
+			idx.function(decl)
+			continue
+		}
+		idx.statement(statement, globals)
+	}
+	return idx
+}
+
+func (idx *index) function(decl ast.FunctionDeclaration) {
+	sym := idx.functions[decl.Name]
+	if sym.function.Pos != decl.Pos {
+		return // Declared more than once; the checker reports it
+	}
+	idx.topLevel = append(idx.topLevel, sym)
+	idx.add(decl.Pos, decl.Name, sym, "")
+
+	params := newScope(nil) // Functions cannot see the variables of main
+	for i, name := range decl.Parameters {
+		param := &symbol{name: name, decl: decl.Span}
+		if i < len(decl.ParamPos) {
+			param.def = decl.ParamPos[i]
+		}
+		if i < len(decl.ParamTypes) {
+			param.typ = decl.ParamTypes[i]
+		}
+		params.names[name] = param
+		idx.symbols = append(idx.symbols, param)
+		idx.add(param.def, name, param, "")
+	}
+	idx.block(decl.Body, newScope(params))
+}
+
+func (idx *index) block(statements []ast.ASTNode, s *scope) {
+	for _, statement := range statements {
+		idx.statement(statement, s)
+	}
+}
+
+func (idx *index) statement(statement ast.ASTNode, s *scope) {
+	switch st := statement.(type) {
+	case ast.ParayuStatement:
+		idx.expr(st.Expression, s)
+	case ast.KelkStatement:
+		idx.assign(st.Identifier, st.Pos, st.Type, st.Span, s)
+	case ast.AssignmentStatement:
+		idx.expr(st.Expression, s)
+		idx.assign(st.Identifier, st.Pos, check.ExpressionType(st.Expression), st.Span, s)
+	case ast.IfStatement:
+		idx.expr(st.Condition, s)
+		idx.block(st.Body, newScope(s))
+		idx.block(st.ElseBody, newScope(s))
+	case ast.WhileStatement:
+		idx.expr(st.Condition, s)
+		idx.block(st.Body, newScope(s))
+	case ast.ForStatement:
+		loopVar := &symbol{name: st.Identifier, def: st.Pos, decl: st.Span, typ: check.Int}
+		if st.Iterable != nil {
+			idx.expr(st.Iterable, s)
+			loopVar.typ = check.LoopVariableType(check.ExpressionType(st.Iterable))
+		} else {
+			idx.expr(st.Start, s)
+			idx.expr(st.End, s)
+			idx.expr(st.Step, s)
+		}
+		loop := newScope(s)
+		loop.names[st.Identifier] = loopVar
+		idx.symbols = append(idx.symbols, loopVar)
+		idx.add(st.Pos, st.Identifier, loopVar, "")
+		idx.block(st.Body, newScope(loop))
+	case ast.IndexAssignmentStatement:
+		idx.expr(st.Target, s)
+		idx.expr(st.Value, s)
+	case ast.ReturnStatement:
+		idx.expr(st.Expression, s)
+	case ast.ExpressionStatement:
+		idx.expr(st.Expression, s)
+	}
+}
+
+// assign records an assignment to name, which defines the variable if it is
+// not visible yet.
+func (idx *index) assign(name string, pos ast.Pos, typ string, span ast.Span, s *scope) {
+	if sym := s.lookup(name); sym != nil {
+		idx.add(pos, name, sym, "")
+		return
+	}
+	sym := &symbol{name: name, def: pos, decl: span, typ: typ}
+	s.names[name] = sym
+	idx.symbols = append(idx.symbols, sym)
+	if s.parent == nil { // Function bodies sit inside a scope of parameters
+		idx.topLevel = append(idx.topLevel, sym)
+	}
+	idx.add(pos, name, sym, "")
+}
+
+func (idx *index) expr(expression ast.ASTNode, s *scope) {
+	switch e := expression.(type) {
+	case ast.Identifier:
+		if sym := s.lookup(e.Name); sym != nil {
+			if sym.typ == "" {
+				sym.typ = e.Type
+			}
+			idx.add(e.Pos, e.Name, sym, e.Type)
+		}
+	case ast.CallExpression:
+		if sym, ok := idx.functions[e.Function]; ok {
+			idx.add(e.Pos, e.Function, sym, e.Type)
+		} else if sym, ok := idx.builtins[e.Function]; ok {
+			idx.add(e.Pos, e.Function, sym, e.Type)
+		}
+		for _, argument := range e.Arguments {
+			idx.expr(argument, s)
+		}
+	case ast.BinaryExpression:
+		idx.expr(e.Left, s)
+		idx.expr(e.Right, s)
+	case ast.UnaryExpression:
+		idx.expr(e.Operand, s)
+	case ast.IndexExpression:
+		idx.expr(e.Collection, s)
+		idx.expr(e.Index, s)
+	case ast.InterpolatedString:
+		for _, part := range e.Parts {
+			idx.expr(part, s)
+		}
+	case ast.ListLiteral:
+		for _, element := range e.Elements {
+			idx.expr(element, s)
+		}
+	case ast.MapLiteral:
+		for i := range e.Keys {
+			idx.expr(e.Keys[i], s)
+			idx.expr(e.Values[i], s)
+		}
+	}
+}
+
+func (idx *index) add(pos ast.Pos, name string, sym *symbol, typ string) {
+	if pos.Line == 0 {
+		return
+	}
+	idx.occurrences = append(idx.occurrences, occurrence{
+		line: pos.Line, col: pos.Col, endCol: pos.Col + utf8.RuneCountInString(name), sym: sym, typ: typ,
+	})
+}
+
+// at returns the occurrence of a name at the given 1-based line and rune
+// column. A column just past the end of a name still counts, since that is
+// where the cursor sits after typing it.
+func (idx *index) at(line, col int) (occurrence, bool) {
+	var after *occurrence
+	for i, o := range idx.occurrences {
+		if o.line != line || col < o.col || col > o.endCol {
+			continue
+		}
+		if col < o.endCol {
+			return o, true
+		}
+		after = &idx.occurrences[i]
+	}
+	if after != nil {
+		return *after, true
+	}
+	return occurrence{}, false
+}
+
+// describe is the hover text for a symbol, given the type of the occurrence
+// under the cursor if the checker recorded one.
+func (sym *symbol) describe(typ string) string {
+	switch {
+	case sym.builtin:
+		return fmt.Sprintf("%s: builtin function", sym.name)
+	case sym.function != nil:
+		return signature(*sym.function)
+	}
+	if typ == "" {
+		typ = sym.typ
+	}
+	if typ == "" {
+		return fmt.Sprintf("%s: type not known", sym.name)
+	}
+	return fmt.Sprintf("%s: %s", sym.name, typ)
+}
+
+// signature spells out a function with the types the checker inferred for
+// it, if it has been called.
+func signature(decl ast.FunctionDeclaration) string {
+	params := make([]string, len(decl.Parameters))
+	for i, name := range decl.Parameters {
+		params[i] = name
+		if i < len(decl.ParamTypes) && decl.ParamTypes[i] != "" {
+			params[i] += " " + decl.ParamTypes[i]
+		}
+	}
+	code := fmt.Sprintf("pani %s(%s)", decl.Name, strings.Join(params, ", "))
+	if decl.ReturnType != "" {
+		code += " " + decl.ReturnType
+	}
+	return code
+}
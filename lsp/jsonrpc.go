@@ -0,0 +1,101 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// request is an incoming JSON-RPC 2.0 request, or a notification if it has
+// no ID.
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+// response answers the request with the same ID. Exactly one of Result and
+// Error is sent, and Result is sent even when it is null.
+type response struct {
+	ID     *json.RawMessage
+	Result interface{}
+	Error  *responseError
+}
+
+func (r response) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{"jsonrpc": "2.0", "id": r.ID}
+	if r.Error != nil {
+		fields["error"] = r.Error
+	} else {
+		fields["result"] = r.Result
+	}
+	return json.Marshal(fields)
+}
+
+// notification is a message the server sends without expecting an answer.
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC error codes.
+const (
+	parseError     = -32700
+	invalidParams  = -32602
+	methodNotFound = -32601
+	internalError  = -32603
+)
+
+// ReadMessage reads one message framed by a Content-Length header, as LSP
+// sends them over stdio, and returns its JSON body.
+func ReadMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("message without a Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// WriteMessage encodes v as JSON and writes it with a Content-Length header.
+func WriteMessage(w io.Writer, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
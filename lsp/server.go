@@ -0,0 +1,249 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Rohith04MVK/malang/check"
+	"github.com/Rohith04MVK/malang/lexer"
+)
+
+// Server is a Language Server Protocol server for .malang files. It keeps
+// every open document analysed, publishes its diagnostics whenever it
+// changes, and answers hover, go-to-definition, completion and document
+// symbol requests from the analysis.
+type Server struct {
+	in        *bufio.Reader
+	out       io.Writer
+	documents map[string]*document
+	shutdown  bool  // The client sent shutdown, so exit is expected
+	writeErr  error // First failure to write to the client, which ends Run
+}
+
+// New creates a server that reads requests from in and writes responses and
+// notifications to out, framed as LSP frames them on stdio.
+func New(in io.Reader, out io.Writer) *Server {
+	return &Server{in: bufio.NewReader(in), out: out, documents: make(map[string]*document)}
+}
+
+// Run serves requests until the client sends exit or the input ends. It
+// returns nil only if the client shut the server down properly first.
+func (s *Server) Run() error {
+	for {
+		body, err := ReadMessage(s.in)
+		if err != nil {
+			if err == io.EOF && s.shutdown {
+				return nil
+			}
+			return err
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.write(response{Error: &responseError{Code: parseError, Message: err.Error()}})
+			if s.writeErr != nil {
+				return s.writeErr
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches one request or notification. Only failures to write to
+// the client are returned; anything else becomes an error response.
+func (s *Server) handle(req request) error {
+	result, respErr := s.call(req)
+	if req.ID != nil { // Notifications get no answer, even when they fail
+		s.write(response{ID: req.ID, Result: result, Error: respErr})
+	}
+	return s.writeErr
+}
+
+func (s *Server) call(req request) (result interface{}, respErr *responseError) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, respErr = nil, &responseError{Code: internalError, Message: fmt.Sprint(r)}
+		}
+	}()
+
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":       1, // The client sends the whole text on every change
+				"hoverProvider":          true,
+				"definitionProvider":     true,
+				"completionProvider":     map[string]interface{}{},
+				"documentSymbolProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "malang"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalid(err)
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalid(err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.update(params.TextDocument.URI, text)
+		return nil, nil
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalid(err)
+		}
+		delete(s.documents, params.TextDocument.URI)
+		// Clear the closed file's diagnostics from the client's problem list.
+		s.publish(PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+		return nil, nil
+	case "textDocument/hover":
+		return s.positionRequest(req, s.hover)
+	case "textDocument/definition":
+		return s.positionRequest(req, s.definition)
+	case "textDocument/completion":
+		return s.positionRequest(req, s.completion)
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalid(err)
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			return []DocumentSymbol{}, nil
+		}
+		return s.documentSymbols(doc), nil
+	default:
+		return nil, &responseError{Code: methodNotFound, Message: "method not supported: " + req.Method}
+	}
+}
+
+// positionRequest decodes the parameters shared by requests about a place
+// in a document and passes the document and that place to answer. A
+// request about a document that is not open gets a null result.
+func (s *Server) positionRequest(req request, answer func(doc *document, line, col int) interface{}) (interface{}, *responseError) {
+	var params TextDocumentPositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, invalid(err)
+	}
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	line, col := doc.source(params.Position)
+	return answer(doc, line, col), nil
+}
+
+// update analyses the new text of a document and publishes its diagnostics.
+func (s *Server) update(uri, text string) {
+	doc := analyze(uri, text)
+	s.documents[uri] = doc
+	s.publish(PublishDiagnosticsParams{URI: uri, Diagnostics: doc.diagnostics()})
+}
+
+func (s *Server) publish(params PublishDiagnosticsParams) {
+	s.write(notification{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics", Params: params})
+}
+
+func (s *Server) hover(doc *document, line, col int) interface{} {
+	o, ok := doc.index.at(line, col)
+	if !ok {
+		return nil
+	}
+	r := doc.rangeOf(o.line, o.col, o.endCol)
+	return Hover{Contents: MarkupContent{Kind: "markdown", Value: "```\n" + o.sym.describe(o.typ) + "\n```"}, Range: &r}
+}
+
+// definition answers with where a name was first assigned, or where a
+// function or parameter was declared.
+func (s *Server) definition(doc *document, line, col int) interface{} {
+	o, ok := doc.index.at(line, col)
+	if !ok || o.sym.def.Line == 0 {
+		return nil
+	}
+	return Location{URI: doc.uri, Range: doc.nameRange(o.sym.def, o.sym.name)}
+}
+
+// completion offers the keywords, the builtin functions and every name the
+// document defines.
+func (s *Server) completion(doc *document, line, col int) interface{} {
+	items := []CompletionItem{}
+	for kind := lexer.TokParayu; kind <= lexer.TokThettu; kind++ {
+		items = append(items, CompletionItem{Label: kind.String(), Kind: CompletionKeyword})
+	}
+	for _, name := range check.Builtins() {
+		items = append(items, CompletionItem{Label: name, Kind: CompletionFunction, Detail: "builtin function"})
+	}
+
+	seen := map[string]bool{}
+	var names []CompletionItem
+	for _, sym := range doc.index.symbols {
+		if seen[sym.name] {
+			continue
+		}
+		seen[sym.name] = true
+		item := CompletionItem{Label: sym.name, Kind: CompletionVariable, Detail: sym.typ}
+		if sym.function != nil {
+			item.Kind, item.Detail = CompletionFunction, signature(*sym.function)
+		}
+		names = append(names, item)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].Label < names[j].Label })
+	return append(items, names...)
+}
+
+// documentSymbols lists the functions and top-level variables of a
+// document, in the order they appear.
+func (s *Server) documentSymbols(doc *document) []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+	for _, sym := range doc.index.topLevel {
+		symbol := DocumentSymbol{
+			Name:           sym.name,
+			Detail:         sym.typ,
+			Kind:           SymbolVariable,
+			Range:          doc.spanRange(sym.decl),
+			SelectionRange: doc.nameRange(sym.def, sym.name),
+		}
+		if sym.function != nil {
+			symbol.Kind, symbol.Detail = SymbolFunction, signature(*sym.function)
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// write sends a message to the client, remembering the first failure.
+func (s *Server) write(v interface{}) {
+	if s.writeErr == nil {
+		s.writeErr = WriteMessage(s.out, v)
+	}
+}
+
+func invalid(err error) *responseError {
+	return &responseError{Code: invalidParams, Message: err.Error()}
+}
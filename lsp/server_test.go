@@ -0,0 +1,198 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rohith04MVK/malang/lsp"
+)
+
+const program = `pani double(n) {
+    thirich_kodukk n * 2
+}
+xs = [1, 2]
+oron_ayi x edukk (xs) {
+    parayu(double(x))
+}
+`
+
+const uri = "file:///test.malang"
+
+// client talks to a server over pipes, as an editor would over stdio. It
+// reads what the server sends as it comes, since the server cannot read the
+// next request while a notification it writes is waiting to be read.
+type client struct {
+	t             *testing.T
+	in            *io.PipeWriter
+	received      chan []byte // Messages from the server; closed when it stops writing
+	id            int
+	notifications []json.RawMessage // Notifications received so far, oldest first
+}
+
+func newClient(t *testing.T, in *io.PipeWriter, out io.Reader) *client {
+	c := &client{t: t, in: in, received: make(chan []byte, 16)}
+	go func() {
+		r := bufio.NewReader(out)
+		for {
+			body, err := lsp.ReadMessage(r)
+			if err != nil {
+				close(c.received)
+				return
+			}
+			c.received <- body
+		}
+	}()
+	return c
+}
+
+// message is any message the server sends: a response or a notification.
+type message struct {
+	ID     *int             `json:"id"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params"`
+	Result json.RawMessage  `json:"result"`
+	Error  *json.RawMessage `json:"error"`
+}
+
+func (c *client) send(v interface{}) {
+	c.t.Helper()
+	if err := lsp.WriteMessage(c.in, v); err != nil {
+		c.t.Fatalf("writing to the server: %v", err)
+	}
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// request sends a request and decodes the result of its response into
+// result, keeping any notifications that arrive first.
+func (c *client) request(method string, params interface{}, result interface{}) {
+	c.t.Helper()
+	c.id++
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": c.id, "method": method, "params": params})
+	for {
+		var body []byte
+		select {
+		case b, ok := <-c.received:
+			if !ok {
+				c.t.Fatalf("%s: the server stopped before answering", method)
+			}
+			body = b
+		case <-time.After(5 * time.Second):
+			c.t.Fatalf("%s: no answer from the server", method)
+		}
+		var m message
+		if err := json.Unmarshal(body, &m); err != nil {
+			c.t.Fatalf("%s: bad message %s: %v", method, body, err)
+		}
+		if m.ID == nil {
+			c.notifications = append(c.notifications, body)
+			continue
+		}
+		if *m.ID != c.id {
+			c.t.Fatalf("%s: response to request %d, want %d", method, *m.ID, c.id)
+		}
+		if m.Error != nil {
+			c.t.Fatalf("%s: error %s", method, *m.Error)
+		}
+		if err := json.Unmarshal(m.Result, result); err != nil {
+			c.t.Fatalf("%s: bad result %s: %v", method, m.Result, err)
+		}
+		return
+	}
+}
+
+func position(line, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri},
+		"position":     lsp.Position{Line: line, Character: character},
+	}
+}
+
+func TestSession(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- lsp.New(inR, outW).Run()
+		outW.Close()
+	}()
+	c := newClient(t, inW, outR)
+
+	var initialized struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	c.request("initialize", map[string]interface{}{}, &initialized)
+	for _, capability := range []string{"hoverProvider", "definitionProvider", "documentSymbolProvider"} {
+		if initialized.Capabilities[capability] != true {
+			t.Errorf("initialize: %s is %v, want true", capability, initialized.Capabilities[capability])
+		}
+	}
+	c.notify("initialized", map[string]interface{}{})
+	c.notify("textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, Version: 1, Text: program},
+	})
+
+	// The loop variable x, in parayu(double(x)), takes the list's element type.
+	var hover lsp.Hover
+	c.request("textDocument/hover", position(5, 18), &hover)
+	if !strings.Contains(hover.Contents.Value, "x: int") {
+		t.Errorf("hover over x: got %q, want x: int", hover.Contents.Value)
+	}
+	c.request("textDocument/hover", position(5, 12), &hover)
+	if !strings.Contains(hover.Contents.Value, "pani double(n int) int") {
+		t.Errorf("hover over double: got %q, want its signature", hover.Contents.Value)
+	}
+
+	// The xs in the loop header goes back to the assignment on line 3.
+	var definition lsp.Location
+	c.request("textDocument/definition", position(4, 18), &definition)
+	want := lsp.Range{Start: lsp.Position{Line: 3, Character: 0}, End: lsp.Position{Line: 3, Character: 2}}
+	if definition.URI != uri || definition.Range != want {
+		t.Errorf("definition of xs: got %+v, want %v at %+v", definition, uri, want)
+	}
+
+	var symbols []lsp.DocumentSymbol
+	c.request("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri},
+	}, &symbols)
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Name)
+	}
+	if got := strings.Join(names, " "); got != "double xs" {
+		t.Errorf("document symbols: got %q, want \"double xs\"", got)
+	}
+
+	// Opening the document published its diagnostics, of which it has none.
+	var published []lsp.PublishDiagnosticsParams
+	for _, body := range c.notifications {
+		var m message
+		var params lsp.PublishDiagnosticsParams
+		if json.Unmarshal(body, &m) == nil && m.Method == "textDocument/publishDiagnostics" &&
+			json.Unmarshal(m.Params, &params) == nil {
+			published = append(published, params)
+		}
+	}
+	if len(published) != 1 || published[0].URI != uri || len(published[0].Diagnostics) != 0 {
+		t.Errorf("published diagnostics: got %+v, want none for %s", published, uri)
+	}
+
+	var shutdown interface{}
+	c.request("shutdown", nil, &shutdown)
+	c.notify("exit", nil)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run after shutdown and exit: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server did not stop after exit")
+	}
+}
@@ -5,47 +5,86 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
 	"github.com/Rohith04MVK/malang/codegen"
+	"github.com/Rohith04MVK/malang/diag"
+	"github.com/Rohith04MVK/malang/interp"
 	"github.com/Rohith04MVK/malang/lexer"
+	"github.com/Rohith04MVK/malang/lsp"
 	"github.com/Rohith04MVK/malang/parser"
+	"github.com/Rohith04MVK/malang/repl"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "build" {
+		build(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fmt" {
+		formatFiles(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lsp" {
+		if err := lsp.New(os.Stdin, os.Stdout).Run(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "repl" {
+		if err := repl.New(os.Stdin, os.Stdout).Run(); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Define command-line flags
 	debugTokens := flag.Bool("tokens", false, "Print tokens")
 	debugAST := flag.Bool("ast", false, "Print AST")
 	debugGoCode := flag.Bool("gocode", false, "Print generated Go code")
+	useInterp := flag.Bool("interp", false, "Run the program with the built-in interpreter instead of the Go toolchain")
 	flag.Parse()
 
 	if flag.NArg() != 1 { //check if there is only one non flag argument.
-		fmt.Println("Usage: mylang [options] <filename.mylang>")
+		fmt.Println("Usage: malang [options] <filename.malang>")
+		fmt.Println("       malang build [-o output] <filename.malang>")
+		fmt.Println("       malang fmt [-w] [-d] <filename.malang>...")
+		fmt.Println("       malang repl")
+		fmt.Println("       malang lsp")
 		flag.PrintDefaults() //print all flags and their descriptions
 		return
 	}
 
 	filename := flag.Arg(0) // Use flag.Arg to get positional arguments
-	inputBytes, err := os.ReadFile(filename)
+	source, program := parseFile(filename, *debugTokens, *debugAST)
+
+	// Type errors are reported before anything runs, whichever back end is used.
+	checked, diags, err := check.Check(program)
 	if err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+		report(filename, source, diags)
 	}
 
-	input := string(inputBytes)
-	input = codegen.RemoveComments(input)
-
-	tokens := lexer.Lex(input)
-	if *debugTokens {
-		fmt.Println("Tokens:", tokens)
+	if *useInterp {
+		// The interpreter runs the annotated program so it knows where ints
+		// are promoted to floats.
+		if err := interp.New(os.Stdin, os.Stdout).Run(checked); err != nil {
+			if d, ok := err.(diag.Diagnostic); ok {
+				report(filename, source, []diag.Diagnostic{d})
+			}
+			fmt.Println("Error running program:", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	p := parser.NewParser(tokens)
-	program := p.Parse()
-	if *debugAST {
-		fmt.Println("AST:", program)
+	generatedCode, diags, err := codegen.GenerateCodeDiag(program)
+	if err != nil {
+		report(filename, source, diags)
 	}
-
-	generatedCode := codegen.GenerateCode(program)
 	if *debugGoCode {
 		fmt.Println("Generated Go Code:\n", generatedCode)
 	}
@@ -86,3 +125,45 @@ func main() {
 		os.Remove(tmpFile.Name()) //only delete if runs.
 	}
 }
+
+// parseFile reads, lexes and parses a malang file, reporting any lexical or
+// syntax errors and exiting if there are some.
+func parseFile(filename string, debugTokens, debugAST bool) (source string, program ast.Program) {
+	inputBytes, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Println("Error reading file:", err)
+		os.Exit(1)
+	}
+
+	source = string(inputBytes)
+
+	// Lexical and syntax errors are reported together so that a single run
+	// shows every mistake in the file.
+	tokens, lexDiags, lexErr := lexer.LexDiag(source)
+	if debugTokens {
+		fmt.Println("Tokens:", tokens)
+	}
+
+	p := parser.NewParser(tokens)
+	// The generated code runs from a temporary directory, so the //line
+	// directives that point back at this file need its absolute path.
+	if abs, err := filepath.Abs(filename); err == nil {
+		p.File = abs
+	}
+	program, parseDiags, parseErr := p.ParseDiag()
+	if lexErr != nil || parseErr != nil {
+		report(filename, source, append(lexDiags, parseDiags...))
+	}
+	if debugAST {
+		fmt.Println("AST:", program)
+	}
+	return source, program
+}
+
+// report prints diagnostics with source excerpts and exits with a failure status.
+func report(filename, source string, diags []diag.Diagnostic) {
+	diag.SetFile(diags, filename)
+	diag.Sort(diags)
+	diag.Render(os.Stderr, source, diags)
+	os.Exit(1)
+}
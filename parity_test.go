@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Rohith04MVK/malang/check"
+	"github.com/Rohith04MVK/malang/codegen"
+	"github.com/Rohith04MVK/malang/interp"
+	"github.com/Rohith04MVK/malang/lexer"
+	"github.com/Rohith04MVK/malang/parser"
+)
+
+// parityCases are programs the generated Go code and the interpreter must
+// run the same way: printing output, or, if fails is set, stopping with an
+// error while running. A program the Go compiler rejects fails the test.
+var parityCases = []struct {
+	name   string
+	source string
+	output string
+	fails  bool
+}{
+	{
+		name: "cherkk copies the list",
+		source: `a = [1, 2]
+a = cherkk(a, 9)
+b = cherkk(a, 3)
+c = cherkk(a, 4)
+parayu(b)
+parayu(c)
+`,
+		output: "[1, 2, 9, 3]\n[1, 2, 9, 4]\n",
+	},
+	{
+		name: "a variable may share the name of a function it does not hide",
+		source: `pani f(x) {
+    thirich_kodukk x
+}
+parayu(f(2))
+f = 10
+parayu(f)
+pani g(f) {
+    thirich_kodukk f + 1
+}
+parayu(g(1))
+`,
+		output: "2\n10\n2\n",
+	},
+	{
+		name:   "dividing by a constant 0 fails at run time",
+		source: "parayu(1 / 0)\n",
+		fails:  true,
+	},
+	{
+		name:   "a constant int sum wraps around",
+		source: "parayu(9223372036854775807 + 1)\nparayu(-9223372036854775807 - 2)\n",
+		output: "-9223372036854775808\n9223372036854775807\n",
+	},
+	{
+		name:   "a float divided by a constant 0 is infinite",
+		source: "parayu(1.5 / 0)\n",
+		output: "+Inf\n",
+	},
+	{
+		name:   "a negative index fails at run time",
+		source: "xs = [1, 2]\nparayu(xs[0])\nparayu(xs[-1])\n",
+		fails:  true,
+	},
+	{
+		name:   "a map key may be negative",
+		source: "m = {-1: 2}\nm[-1] = 3\nparayu(m[-1])\n",
+		output: "3\n",
+	},
+	{
+		name: "a bare thirich_kodukk may be followed by more statements",
+		source: `pani f(x) {
+    ith_sheriyano (x > 1) enkil {
+        parayu("big")
+        thirich_kodukk
+    }
+    parayu("small")
+}
+f(2)
+f(0)
+`,
+		output: "big\nsmall\n",
+	},
+}
+
+func TestParity(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is needed to build the generated code")
+	}
+	for _, tc := range parityCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			program := parser.NewParser(lexer.Lex(tc.source)).Parse()
+			checked, _, err := check.Check(program)
+			if err != nil {
+				t.Fatalf("check: %v", err)
+			}
+
+			var interpreted strings.Builder
+			err = interp.New(strings.NewReader(""), &interpreted).Run(checked)
+			if tc.fails != (err != nil) {
+				t.Errorf("interpreter: error %v, want failure %v", err, tc.fails)
+			}
+			if !tc.fails && interpreted.String() != tc.output {
+				t.Errorf("interpreter printed %q, want %q", interpreted.String(), tc.output)
+			}
+
+			dir := t.TempDir()
+			files := map[string]string{"go.mod": goMod, "main.go": codegen.GenerateCode(program)}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			build := exec.Command("go", "build", "-o", "program", ".")
+			build.Dir = dir
+			if message, err := build.CombinedOutput(); err != nil {
+				t.Fatalf("the generated code does not build: %v\n%s\n%s", err, message, files["main.go"])
+			}
+			run := exec.Command(filepath.Join(dir, "program"))
+			compiled, err := run.Output()
+			if tc.fails != (err != nil) {
+				t.Errorf("generated code: error %v, want failure %v", err, tc.fails)
+			}
+			if !tc.fails && string(compiled) != tc.output {
+				t.Errorf("generated code printed %q, want %q", compiled, tc.output)
+			}
+		})
+	}
+}
@@ -7,8 +7,12 @@ import (
 
 func (p *Parser) parseBlock() []ast.ASTNode {
 	statements := []ast.ASTNode{}
-	for p.peek().Type != lexer.TokRBrace && p.peek().Type != lexer.TokEOF {
-		statements = append(statements, p.parseStatement())
+	p.depth++
+	defer func() { p.depth-- }()
+	for p.peek().Kind != lexer.TokRBrace && p.peek().Kind != lexer.TokEOF {
+		if statement, ok := p.parseStatementRecover(); ok {
+			statements = append(statements, statement)
+		}
 	}
 	return statements
 }
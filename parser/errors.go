@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/Rohith04MVK/malang/diag"
+	"github.com/Rohith04MVK/malang/lexer"
+)
+
+// bailout is the panic value used to unwind the parser after a syntax error
+// has been recorded. It never escapes the package.
+type bailout struct{}
+
+// describeKind names a kind of token for error messages.
+func describeKind(kind lexer.Kind) string {
+	switch kind {
+	case lexer.TokIdentifier:
+		return "an identifier"
+	case lexer.TokString:
+		return "a string"
+	case lexer.TokInteger, lexer.TokFloat:
+		return "a number"
+	case lexer.TokEOF:
+		return "end of file"
+	default:
+		return fmt.Sprintf("'%s'", kind)
+	}
+}
+
+func describeToken(token lexer.Token) string {
+	switch token.Kind {
+	case lexer.TokEOF:
+		return "end of file"
+	case lexer.TokString, lexer.TokStringStart:
+		return fmt.Sprintf("string %q", token.Value)
+	case lexer.TokStringMiddle, lexer.TokStringEnd:
+		return "'}'"
+	default:
+		return fmt.Sprintf("'%s'", token.Value)
+	}
+}
+
+// hints suggests a fix when a particular token type was expected but missing.
+var hints = map[lexer.Kind]string{
+	lexer.TokAthengil: "conditions are followed by 'enkil', as in: ith_sheriyano (x < 5) enkil { ... }",
+	lexer.TokEdukk:    "loops are written as: oron_ayi i edukk (1..5) { ... }",
+	lexer.TokRBrace:   "every '{' needs a matching '}'",
+	lexer.TokRParen:   "every '(' needs a matching ')'",
+	lexer.TokRBracket: "every '[' needs a matching ']'",
+	lexer.TokColon:    "map entries are written as key: value",
+}
+
+// report records a syntax error at token without interrupting parsing.
+func (p *Parser) report(token lexer.Token, hint string, format string, args ...interface{}) {
+	if token.Kind == lexer.TokEOF && len(p.tokens) > 1 && p.tokens[len(p.tokens)-2].Unterminated {
+		return // Whatever is missing was swallowed by the string the lexer reported
+	}
+	span := len(token.Value)
+	switch token.Kind {
+	case lexer.TokString, lexer.TokStringStart:
+		span += 2 // The quotes, or the quote and '{', are not part of Value
+	case lexer.TokStringMiddle, lexer.TokStringEnd:
+		span = 1 // Point at the '}' that ends the interpolated expression
+	}
+	d := diag.Errorf(token.Line, token.Col, span, format, args...)
+	d.Hint = hint
+	p.diags = append(p.diags, d)
+}
+
+// errorAt records a syntax error at token and unwinds the parser.
+func (p *Parser) errorAt(token lexer.Token, hint string, format string, args ...interface{}) {
+	p.report(token, hint, format, args...)
+	panic(bailout{})
+}
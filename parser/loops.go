@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/lexer"
+)
+
+// parseLoopBody parses the block of a loop, inside which nirthu and thudaru
+// are allowed.
+func (p *Parser) parseLoopBody(label string) []ast.ASTNode {
+	p.consume(lexer.TokLBrace)
+	p.loops = append(p.loops, label)
+	body := p.parseBlock()
+	p.loops = p.loops[:len(p.loops)-1]
+	p.consume(lexer.TokRBrace)
+	return body
+}
+
+// parseLabelledLoop parses a loop with a name in front of it, as in
+// purathe: oron_ayi i edukk (1..5) { ... }, so that nirthu and thudaru in a
+// loop nested inside can refer to it.
+func (p *Parser) parseLabelledLoop() ast.ASTNode {
+	label := p.consume(lexer.TokIdentifier)
+	p.consume(lexer.TokColon)
+	// Go labels belong to the whole function, so a name can only be used once.
+	if previous, ok := p.labels[label.Value]; ok {
+		p.report(label, "pick another name", "label '%s' is already used on line %d", label.Value, previous.Line)
+	}
+	if p.labels == nil {
+		p.labels = make(map[string]lexer.Token)
+	}
+	p.labels[label.Value] = label
+
+	switch p.peek().Kind {
+	case lexer.TokEllamSheriyano:
+		return p.parseWhileStatement(label, label.Value)
+	case lexer.TokOnninuMumbu:
+		return p.parseForStatement(label, label.Value)
+	default:
+		p.errorAt(p.peek(), "a label names the loop that follows it, as in: purathe: oron_ayi i edukk (1..5) { ... }",
+			"expected a loop after label '%s', got %s", label.Value, describeToken(p.peek()))
+		return nil
+	}
+}
+
+// parseLoopLabel checks that the nirthu or thudaru keyword is inside a loop
+// and parses the label that may follow it. Like the type word after kelk, the
+// label only counts on the same line as the keyword.
+func (p *Parser) parseLoopLabel(keyword lexer.Token) string {
+	if len(p.loops) == 0 {
+		p.report(keyword, "", "%s outside of a loop", keyword.Value)
+	}
+	next := p.peek()
+	if next.Kind != lexer.TokIdentifier || next.Line != keyword.Line {
+		return ""
+	}
+	p.consume(lexer.TokIdentifier)
+	for _, label := range p.loops {
+		if label == next.Value {
+			return next.Value
+		}
+	}
+	if len(p.loops) > 0 {
+		p.report(next, "a label must name a loop that this one is inside", "no loop labelled '%s' around this %s", next.Value, keyword.Value)
+	}
+	return next.Value
+}
@@ -1,16 +1,24 @@
 package parser
 
 import (
-	"fmt"
 	"strconv"
 
 	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/diag"
 	"github.com/Rohith04MVK/malang/lexer"
 )
 
 type Parser struct {
 	tokens []lexer.Token
 	pos    int
+	diags  []diag.Diagnostic
+
+	depth      int                    // Number of enclosing blocks
+	inFunction bool                   // Whether a thirich_kodukk is allowed here
+	loops      []string               // Labels of the enclosing loops, innermost last; "" if unlabelled
+	labels     map[string]lexer.Token // Loop labels declared in the current function or at the top level
+
+	File string // Source file name recorded in every ast.Pos; may be empty
 }
 
 func NewParser(tokens []lexer.Token) *Parser {
@@ -19,76 +27,167 @@ func NewParser(tokens []lexer.Token) *Parser {
 
 func (p *Parser) peek() lexer.Token {
 	if p.pos >= len(p.tokens) {
-		return lexer.Token{Type: lexer.TokEOF}
+		return lexer.Token{Kind: lexer.TokEOF}
 	}
 	return p.tokens[p.pos]
 }
 
-func (p *Parser) consume(expectedType string) lexer.Token {
+func (p *Parser) consume(expected lexer.Kind) lexer.Token {
 	token := p.peek()
-	if token.Type != expectedType {
-		panic(fmt.Sprintf("Expected token type %s, got %s at line %d, col %d", expectedType, token.Type, token.Line, token.Col))
+	if token.Kind != expected {
+		p.errorAt(token, hints[expected], "expected %s, got %s", describeKind(expected), describeToken(token))
 	}
 	p.pos++
 	return token
 }
 
+// Parse parses the whole token stream and panics if it contains a syntax
+// error. Use ParseDiag to get errors back as diagnostics instead.
 func (p *Parser) Parse() ast.Program {
-	program := ast.Program{Statements: []ast.ASTNode{}}
-	for p.peek().Type != lexer.TokEOF {
-		program.Statements = append(program.Statements, p.parseStatement())
+	program, _, err := p.ParseDiag()
+	if err != nil {
+		panic(err)
 	}
 	return program
 }
 
+// ParseDiag parses the whole token stream, recovering from syntax errors so
+// that every error in the input is reported. Statements containing errors are
+// left out of the returned program.
+func (p *Parser) ParseDiag() (ast.Program, []diag.Diagnostic, error) {
+	program := ast.Program{Statements: []ast.ASTNode{}}
+	start := p.peek()
+	for p.peek().Kind != lexer.TokEOF {
+		// A '}' at the top level after an error is almost always the end of
+		// a block whose header failed to parse; skip it quietly rather than
+		// reporting the same mistake twice.
+		if p.peek().Kind == lexer.TokRBrace && len(p.diags) > 0 {
+			p.pos++
+			continue
+		}
+		if statement, ok := p.parseStatementRecover(); ok {
+			program.Statements = append(program.Statements, statement)
+		}
+	}
+	program.Span = ast.Span{Start: p.posOf(start), End: p.endOf(p.peek())}
+	return program, p.diags, diag.Err(p.diags)
+}
+
 func (p *Parser) parseStatement() ast.ASTNode {
-	switch p.peek().Type {
+	start := p.peek()
+	switch start.Kind {
 	case lexer.TokParayu:
 		return p.parseParayuStatement()
 	case lexer.TokKelk:
 		return p.parseKelkStatement()
-	case lexer.TokIdentifier: // Could be assignment or part of expression
-		if p.peekNext().Type == lexer.TokOperator && p.peekNext().Value == "=" {
+	case lexer.TokIdentifier: // Could be assignment, a loop label or part of expression
+		if p.peekNext().Kind == lexer.TokColon {
+			return p.parseLabelledLoop()
+		}
+		if next := p.peekNext().Kind; next == lexer.TokAssign || compoundOperators[next] != "" {
 			return p.parseAssignmentStatement()
 		}
-		fallthrough // otherwise parse as an expression statement (for now, just expressions)
+		expression := p.parseExpression()
+		if index, ok := expression.(ast.IndexExpression); ok && p.atAssignment() {
+			value, compound := p.parseAssignedValue(index, start)
+			return ast.IndexAssignmentStatement{Target: index, Value: value, Compound: compound, Span: p.spanFrom(start)}
+		}
+		return ast.ExpressionStatement{Expression: expression, Span: p.spanFrom(start)}
 	case lexer.TokAadhyamayi:
 		return p.parseIfStatement()
 	case lexer.TokEllamSheriyano:
-		return p.parseWhileStatement()
+		return p.parseWhileStatement(start, "")
 	case lexer.TokOnninuMumbu:
-		return p.parseForStatement()
+		return p.parseForStatement(start, "")
+	case lexer.TokPani:
+		return p.parseFunctionDeclaration()
+	case lexer.TokThirichKodukk:
+		return p.parseReturnStatement()
+	case lexer.TokNirthu:
+		keyword := p.consume(lexer.TokNirthu)
+		label := p.parseLoopLabel(keyword)
+		return ast.BreakStatement{Label: label, Pos: p.posOf(keyword), Span: p.spanFrom(start)}
+	case lexer.TokThudaru:
+		keyword := p.consume(lexer.TokThudaru)
+		label := p.parseLoopLabel(keyword)
+		return ast.ContinueStatement{Label: label, Pos: p.posOf(keyword), Span: p.spanFrom(start)}
 	default:
-		return p.parseExpression()
+		expression := p.parseExpression()
+		return ast.ExpressionStatement{Expression: expression, Span: p.spanFrom(start)}
 	}
 }
 
 func (p *Parser) parseParayuStatement() ast.ASTNode {
-	p.consume(lexer.TokParayu)
+	keyword := p.consume(lexer.TokParayu)
 	p.consume(lexer.TokLParen)
 	expression := p.parseExpression()
 	p.consume(lexer.TokRParen)
-	return ast.ParayuStatement{Expression: expression}
+	return ast.ParayuStatement{Expression: expression, Span: p.spanFrom(keyword)}
+}
+
+// inputTypes maps the words that may follow kelk(...) to the type of value
+// read. Without one, kelk reads a string.
+var inputTypes = map[string]string{
+	"ennam":      "int",
+	"dashamsham": "float64",
 }
 
 func (p *Parser) parseKelkStatement() ast.ASTNode {
-	p.consume(lexer.TokKelk)
+	keyword := p.consume(lexer.TokKelk)
 	p.consume(lexer.TokLParen)
-	identifier := p.consume(lexer.TokIdentifier).Value
-	p.consume(lexer.TokRParen)
+	identifier := p.consume(lexer.TokIdentifier)
+	rparen := p.consume(lexer.TokRParen)
 
-	return ast.KelkStatement{Identifier: identifier}
+	// The type word is not a keyword, so it only counts on the same line;
+	// otherwise it is the start of the next statement.
+	inputType := "string"
+	if next := p.peek(); next.Kind == lexer.TokIdentifier && next.Line == rparen.Line {
+		if t, ok := inputTypes[next.Value]; ok {
+			p.consume(lexer.TokIdentifier)
+			inputType = t
+		}
+	}
+
+	return ast.KelkStatement{Identifier: identifier.Value, Type: inputType, Pos: p.posOf(identifier), Span: p.spanFrom(keyword)}
 }
 
 func (p *Parser) parseAssignmentStatement() ast.ASTNode {
-	identifier := p.consume(lexer.TokIdentifier).Value
-	p.consume(lexer.TokOperator) // We already know it's an '='
-	expression := p.parseExpression()
-	return ast.AssignmentStatement{Identifier: identifier, Expression: expression}
+	identifier := p.consume(lexer.TokIdentifier)
+	target := ast.Identifier{Name: identifier.Value, Pos: p.posOf(identifier), Span: p.spanFrom(identifier)}
+	expression, compound := p.parseAssignedValue(target, identifier)
+	return ast.AssignmentStatement{Identifier: identifier.Value, Expression: expression, Compound: compound, Pos: p.posOf(identifier), Span: p.spanFrom(identifier)}
+}
+
+// compoundOperators maps each compound assignment to the operator it applies.
+var compoundOperators = map[lexer.Kind]string{
+	lexer.TokPlusAssign:     "+",
+	lexer.TokMinusAssign:    "-",
+	lexer.TokMultiplyAssign: "*",
+	lexer.TokDivideAssign:   "/",
+	lexer.TokModuloAssign:   "%",
+	lexer.TokPowerAssign:    "**",
+}
+
+func (p *Parser) atAssignment() bool {
+	next := p.peek().Kind
+	return next == lexer.TokAssign || compoundOperators[next] != ""
+}
+
+// parseAssignedValue parses the '=' or compound operator of an assignment to
+// target, which began at start, and the expression after it. x += v is
+// spelled out as x = x + v, spanning the whole statement, so nothing past
+// the parser needs to know about compound assignment.
+func (p *Parser) parseAssignedValue(target ast.ASTNode, start lexer.Token) (value ast.ASTNode, compound bool) {
+	operator := p.consume(p.peek().Kind)
+	value = p.parseExpression()
+	if operator.Kind == lexer.TokAssign {
+		return value, false
+	}
+	return ast.BinaryExpression{Left: target, Operator: compoundOperators[operator.Kind], Right: value, Pos: p.posOf(operator), Span: p.spanFrom(start)}, true
 }
 
 func (p *Parser) parseIfStatement() ast.ASTNode {
-	p.consume(lexer.TokAadhyamayi)
+	keyword := p.consume(lexer.TokAadhyamayi)
 	p.consume(lexer.TokLParen)
 	condition := p.parseExpression()
 	p.consume(lexer.TokRParen)
@@ -97,106 +196,355 @@ func (p *Parser) parseIfStatement() ast.ASTNode {
 	body := p.parseBlock()
 	p.consume(lexer.TokRBrace)
 
-	var elseBody []ast.ASTNode
-	if p.peek().Type == lexer.TokIlla {
+	statement := ast.IfStatement{Condition: condition, Body: body}
+	if p.peek().Kind == lexer.TokIlla {
 		p.consume(lexer.TokIlla)
-		p.consume(lexer.TokLBrace)
-		elseBody = p.parseBlock()
-		p.consume(lexer.TokRBrace)
+		if p.peek().Kind == lexer.TokAadhyamayi {
+			// alle ith_sheriyano chains onto another if statement, which
+			// becomes the whole else block.
+			statement.ElseBody = []ast.ASTNode{p.parseIfStatement()}
+			statement.ElseIf = true
+		} else {
+			p.consume(lexer.TokLBrace)
+			statement.ElseBody = p.parseBlock()
+			p.consume(lexer.TokRBrace)
+		}
 	}
-
-	return ast.IfStatement{Condition: condition, Body: body, ElseBody: elseBody}
+	statement.Span = p.spanFrom(keyword)
+	return statement
 }
 
-func (p *Parser) parseWhileStatement() ast.ASTNode {
+// parseWhileStatement parses an ellam_sheriyano loop. start is the loop's
+// first token, which is its label if it has one.
+func (p *Parser) parseWhileStatement(start lexer.Token, label string) ast.ASTNode {
 	p.consume(lexer.TokEllamSheriyano)
 	p.consume(lexer.TokLParen)
 	condition := p.parseExpression()
 	p.consume(lexer.TokRParen)
 	p.consume(lexer.TokAthengil)
-	p.consume(lexer.TokLBrace)
-	body := p.parseBlock()
-	p.consume(lexer.TokRBrace)
+	body := p.parseLoopBody(label)
 
-	return ast.WhileStatement{Condition: condition, Body: body}
+	return ast.WhileStatement{Condition: condition, Body: body, Label: label, Span: p.spanFrom(start)}
 }
 
-func (p *Parser) parseForStatement() ast.ASTNode {
+// parseForStatement parses an oron_ayi loop. start is the loop's first
+// token, which is its label if it has one.
+func (p *Parser) parseForStatement(start lexer.Token, label string) ast.ASTNode {
 	p.consume(lexer.TokOnninuMumbu)
-	identifier := p.consume(lexer.TokIdentifier).Value
+	identifier := p.consume(lexer.TokIdentifier)
 	p.consume(lexer.TokEdukk)
 	p.consume(lexer.TokLParen)
-	start := p.parseExpression()
-	p.consume(lexer.TokRange)
-	end := p.parseExpression()
+	loop := ast.ForStatement{Identifier: identifier.Value, Label: label, Pos: p.posOf(identifier)}
+	// (start..end) loops over a range and (xs) over the elements of a list.
+	first := p.parseExpression()
+	if kind := p.peek().Kind; kind == lexer.TokRange || kind == lexer.TokRangeExclusive {
+		loop.Exclusive = p.consume(kind).Kind == lexer.TokRangeExclusive
+		loop.Start, loop.End = first, p.parseExpression()
+		// idavittu ("at intervals of") is not a keyword, since it can only
+		// appear here.
+		if next := p.peek(); next.Kind == lexer.TokIdentifier && next.Value == "idavittu" {
+			p.consume(lexer.TokIdentifier)
+			loop.Step = p.parseExpression()
+		}
+	} else {
+		loop.Iterable = first
+	}
+	p.consume(lexer.TokRParen)
+
+	loop.Body = p.parseLoopBody(label)
+	loop.Span = p.spanFrom(start)
+	return loop
+}
+
+func (p *Parser) parseFunctionDeclaration() ast.ASTNode {
+	keyword := p.consume(lexer.TokPani)
+	if p.depth > 0 {
+		p.report(keyword, "move the pani declaration out of the block", "functions can only be declared at the top level")
+	}
+	name := p.consume(lexer.TokIdentifier)
+	if name.Value == "main" {
+		p.report(name, "pick another name", "'main' is reserved for the generated program")
+	}
+	p.consume(lexer.TokLParen)
+	parameters := []string{}
+	var paramPos []ast.Pos
+	seen := map[string]bool{}
+	for p.peek().Kind != lexer.TokRParen {
+		if len(parameters) > 0 {
+			p.consume(lexer.TokComma)
+		}
+		parameter := p.consume(lexer.TokIdentifier)
+		if seen[parameter.Value] {
+			p.report(parameter, "", "duplicate parameter '%s'", parameter.Value)
+		}
+		seen[parameter.Value] = true
+		parameters = append(parameters, parameter.Value)
+		paramPos = append(paramPos, p.posOf(parameter))
+	}
 	p.consume(lexer.TokRParen)
 
 	p.consume(lexer.TokLBrace)
+	// A function body starts outside of any loop, and its labels are its own,
+	// as in the Go function it becomes.
+	outer, outerLoops, outerLabels := p.inFunction, p.loops, p.labels
+	p.inFunction, p.loops, p.labels = true, nil, nil
 	body := p.parseBlock()
+	p.inFunction, p.loops, p.labels = outer, outerLoops, outerLabels
 	p.consume(lexer.TokRBrace)
-	return ast.ForStatement{Identifier: identifier, Start: start, End: end, Body: body}
+
+	return ast.FunctionDeclaration{Name: name.Value, Parameters: parameters, ParamPos: paramPos, Body: body, Pos: p.posOf(name), Span: p.spanFrom(keyword)}
+}
+
+func (p *Parser) parseReturnStatement() ast.ASTNode {
+	keyword := p.consume(lexer.TokThirichKodukk)
+	if !p.inFunction {
+		p.report(keyword, "", "thirich_kodukk outside of a function")
+	}
+	// The value, if there is one, starts on the keyword's line, as the
+	// label after nirthu does; a bare thirich_kodukk ends at the line's end.
+	statement := ast.ReturnStatement{Pos: p.posOf(keyword)}
+	if next := p.peek(); next.Kind != lexer.TokRBrace && next.Kind != lexer.TokEOF && next.Line == keyword.EndLine {
+		statement.Expression = p.parseExpression()
+	}
+	statement.Span = p.spanFrom(keyword)
+	return statement
 }
 
 func (p *Parser) parseExpression() ast.ASTNode {
-	return p.parseComparison()
+	return p.parseBinary(1)
+}
+
+// binaryPrecedence gives how tightly each binary operator binds; higher
+// binds tighter. All of them group to the left, so a - b - c is (a - b) - c.
+// Exponentiation is not in the table: it binds tighter than the unary
+// operators, so parsePower handles it below them.
+var binaryPrecedence = map[lexer.Kind]int{
+	lexer.TokOr:           1,
+	lexer.TokAnd:          2,
+	lexer.TokEqual:        3,
+	lexer.TokNotEqual:     3,
+	lexer.TokLess:         3,
+	lexer.TokLessEqual:    3,
+	lexer.TokGreater:      3,
+	lexer.TokGreaterEqual: 3,
+	lexer.TokPlus:         4,
+	lexer.TokMinus:        4,
+	lexer.TokMultiply:     5,
+	lexer.TokDivide:       5,
+	lexer.TokModulo:       5,
+}
+
+// Precedence returns how tightly the binary operator spelled operator
+// binds, as in binaryPrecedence, or 0 if it is not in the table. Tools that
+// print expressions back use it to put back the parentheses the AST lost.
+func Precedence(operator string) int {
+	for kind, precedence := range binaryPrecedence {
+		if kind.String() == operator {
+			return precedence
+		}
+	}
+	return 0
 }
 
-func (p *Parser) parseComparison() ast.ASTNode {
-	left := p.parseTerm() // Use parseTerm here
-	for p.peek().Type == lexer.TokOperator &&
-		(p.peek().Value == "==" || p.peek().Value == "<" ||
-			p.peek().Value == ">" || p.peek().Value == "<=" ||
-			p.peek().Value == ">=" || p.peek().Value == "!=") {
-		operator := p.consume(lexer.TokOperator).Value
-		right := p.parseTerm() // And here
-		left = ast.BinaryExpression{Left: left, Operator: operator, Right: right}
+// parseBinary parses a chain of binary operators that bind at least as
+// tightly as minPrecedence, by precedence climbing: the right operand of an
+// operator only takes in operators that bind tighter than it.
+func (p *Parser) parseBinary(minPrecedence int) ast.ASTNode {
+	start := p.peek()
+	left := p.parseUnary()
+	for {
+		precedence, ok := binaryPrecedence[p.peek().Kind]
+		if !ok || precedence < minPrecedence {
+			return left
+		}
+		operator := p.consume(p.peek().Kind)
+		right := p.parseBinary(precedence + 1)
+		left = ast.BinaryExpression{Left: left, Operator: operator.Value, Right: right, Pos: p.posOf(operator), Span: p.spanFrom(start)}
 	}
-	return left
 }
-func (p *Parser) parseTerm() ast.ASTNode {
-	left := p.parseFactor()
 
-	for p.peek().Type == lexer.TokOperator && (p.peek().Value == "+" || p.peek().Value == "-") {
-		operator := p.consume(lexer.TokOperator).Value
-		right := p.parseFactor()
-		left = ast.BinaryExpression{Left: left, Operator: operator, Right: right}
+func (p *Parser) parseUnary() ast.ASTNode {
+	if kind := p.peek().Kind; kind == lexer.TokNot || kind == lexer.TokMinus {
+		operator := p.consume(kind)
+		operand := p.parseUnary()
+		return ast.UnaryExpression{Operator: operator.Value, Operand: operand, Pos: p.posOf(operator), Span: p.spanFrom(operator)}
 	}
-	return left
+	return p.parsePower()
 }
 
-func (p *Parser) parseFactor() ast.ASTNode {
-	left := p.parsePrimary()
-	for p.peek().Type == lexer.TokOperator && (p.peek().Value == "*" || p.peek().Value == "/") {
-		operator := p.consume(lexer.TokOperator).Value
-		right := p.parsePrimary()
-		left = ast.BinaryExpression{Left: left, Operator: operator, Right: right}
+// parsePower parses exponentiation, which binds tighter than the unary
+// operators on its left, so -2 ** 2 is -(2 ** 2), and groups to the right,
+// so 2 ** 3 ** 2 is 2 ** (3 ** 2). The exponent may have a sign of its own.
+func (p *Parser) parsePower() ast.ASTNode {
+	start := p.peek()
+	base := p.parsePostfix()
+	if p.peek().Kind == lexer.TokPower {
+		operator := p.consume(lexer.TokPower)
+		exponent := p.parseUnary()
+		return ast.BinaryExpression{Left: base, Operator: operator.Value, Right: exponent, Pos: p.posOf(operator), Span: p.spanFrom(start)}
 	}
-	return left
+	return base
+}
+
+// parsePostfix parses a primary expression followed by any number of
+// indexes, as in grid[i][j].
+func (p *Parser) parsePostfix() ast.ASTNode {
+	start := p.peek()
+	expression := p.parsePrimary()
+	for p.peek().Kind == lexer.TokLBracket {
+		bracket := p.consume(lexer.TokLBracket)
+		index := p.parseExpression()
+		p.consume(lexer.TokRBracket)
+		expression = ast.IndexExpression{Collection: expression, Index: index, Pos: p.posOf(bracket), Span: p.spanFrom(start)}
+	}
+	return expression
 }
 
 func (p *Parser) parsePrimary() ast.ASTNode {
-	switch p.peek().Type {
+	switch p.peek().Kind {
 	case lexer.TokInteger:
-		value, _ := strconv.Atoi(p.consume(lexer.TokInteger).Value)
-		return ast.IntegerLiteral{Value: value}
+		token := p.consume(lexer.TokInteger)
+		value, err := strconv.Atoi(token.Value)
+		if err != nil {
+			p.report(token, "", "integer literal %s is too large", token.Value)
+		}
+		return ast.IntegerLiteral{Value: value, Pos: p.posOf(token), Span: p.spanFrom(token)}
+	case lexer.TokFloat:
+		token := p.consume(lexer.TokFloat)
+		value, err := strconv.ParseFloat(token.Value, 64)
+		if err != nil {
+			p.report(token, "", "float literal %s is out of range", token.Value)
+		}
+		return ast.FloatLiteral{Value: value, Text: token.Value, Pos: p.posOf(token), Span: p.spanFrom(token)}
 	case lexer.TokString:
-		return ast.StringLiteral{Value: p.consume(lexer.TokString).Value}
+		token := p.consume(lexer.TokString)
+		return ast.StringLiteral{Value: token.Value, Text: token.Text, Pos: p.posOf(token), Span: p.spanFrom(token)}
+	case lexer.TokStringStart:
+		return p.parseInterpolatedString()
+	case lexer.TokSheri, lexer.TokThettu:
+		token := p.consume(p.peek().Kind)
+		return ast.BooleanLiteral{Value: token.Kind == lexer.TokSheri, Pos: p.posOf(token), Span: p.spanFrom(token)}
 	case lexer.TokIdentifier:
-		name := p.consume(lexer.TokIdentifier).Value
-		return ast.Identifier{Name: name, Type: ""}
+		name := p.consume(lexer.TokIdentifier)
+		if p.peek().Kind == lexer.TokLParen {
+			return p.parseCallArguments(name)
+		}
+		return ast.Identifier{Name: name.Value, Type: "", Pos: p.posOf(name), Span: p.spanFrom(name)}
 	case lexer.TokLParen:
 		p.consume(lexer.TokLParen)
 		expression := p.parseExpression()
 		p.consume(lexer.TokRParen)
 		return expression
+	case lexer.TokLBracket:
+		return p.parseListLiteral()
+	case lexer.TokLBrace:
+		return p.parseMapLiteral()
 	default:
-		panic(fmt.Sprintf("Unexpected token in expression: %s", p.peek().Type))
+		p.errorAt(p.peek(), "", "unexpected %s in expression", describeToken(p.peek()))
+		return nil
+	}
+}
+
+// parseInterpolatedString parses a string with expressions in braces, which
+// the lexer splits into the text before each '{', the tokens of the
+// expression and the text after the '}'.
+func (p *Parser) parseInterpolatedString() ast.ASTNode {
+	start := p.consume(lexer.TokStringStart)
+	literal := ast.InterpolatedString{Pos: p.posOf(start)}
+	text := start
+	for {
+		// The token's text is "abc{, }abc{ or }abc": drop the delimiters,
+		// of which an unterminated string has only the first, if any.
+		inner := text.Text
+		if !text.Unterminated {
+			inner = inner[:len(inner)-1]
+		}
+		if inner != "" {
+			inner = inner[1:]
+		}
+		literal.Parts = append(literal.Parts, ast.StringLiteral{
+			Value: text.Value, Text: inner, Pos: p.posOf(text), Span: p.spanFrom(text),
+		})
+		if text.Kind == lexer.TokStringEnd {
+			break
+		}
+		literal.Parts = append(literal.Parts, p.parseExpression())
+		text = p.peek()
+		if text.Kind != lexer.TokStringMiddle && text.Kind != lexer.TokStringEnd {
+			p.errorAt(text, "an expression in a string is written in braces, as in \"Count: {ennam}\"",
+				"expected '}' after the expression in the string, got %s", describeToken(text))
+		}
+		p.consume(text.Kind)
+	}
+	literal.Span = p.spanFrom(start)
+	return literal
+}
+
+func (p *Parser) parseListLiteral() ast.ASTNode {
+	bracket := p.consume(lexer.TokLBracket)
+	elements := []ast.ASTNode{}
+	for p.peek().Kind != lexer.TokRBracket {
+		if len(elements) > 0 {
+			p.consume(lexer.TokComma)
+		}
+		elements = append(elements, p.parseExpression())
+	}
+	p.consume(lexer.TokRBracket)
+	return ast.ListLiteral{Elements: elements, Pos: p.posOf(bracket), Span: p.spanFrom(bracket)}
+}
+
+func (p *Parser) parseMapLiteral() ast.ASTNode {
+	brace := p.consume(lexer.TokLBrace)
+	literal := ast.MapLiteral{Keys: []ast.ASTNode{}, Values: []ast.ASTNode{}, Pos: p.posOf(brace)}
+	for p.peek().Kind != lexer.TokRBrace {
+		if len(literal.Keys) > 0 {
+			p.consume(lexer.TokComma)
+		}
+		literal.Keys = append(literal.Keys, p.parseExpression())
+		p.consume(lexer.TokColon)
+		literal.Values = append(literal.Values, p.parseExpression())
+	}
+	p.consume(lexer.TokRBrace)
+	literal.Span = p.spanFrom(brace)
+	return literal
+}
+
+func (p *Parser) parseCallArguments(function lexer.Token) ast.ASTNode {
+	p.consume(lexer.TokLParen)
+	arguments := []ast.ASTNode{}
+	for p.peek().Kind != lexer.TokRParen {
+		if len(arguments) > 0 {
+			p.consume(lexer.TokComma)
+		}
+		arguments = append(arguments, p.parseExpression())
+	}
+	p.consume(lexer.TokRParen)
+	return ast.CallExpression{Function: function.Value, Arguments: arguments, Pos: p.posOf(function), Span: p.spanFrom(function)}
+}
+
+// posOf is the position where token starts.
+func (p *Parser) posOf(token lexer.Token) ast.Pos {
+	return ast.Pos{File: p.File, Offset: token.Offset, Line: token.Line, Col: token.Col}
+}
+
+// endOf is the position just past the end of token.
+func (p *Parser) endOf(token lexer.Token) ast.Pos {
+	return ast.Pos{File: p.File, Offset: token.EndOffset, Line: token.EndLine, Col: token.EndCol}
+}
+
+// spanFrom is the span of a node that starts at start and ends with the
+// token consumed last.
+func (p *Parser) spanFrom(start lexer.Token) ast.Span {
+	last := start
+	if p.pos > 0 && p.pos <= len(p.tokens) {
+		last = p.tokens[p.pos-1]
 	}
+	return ast.Span{Start: p.posOf(start), End: p.endOf(last)}
 }
 
 func (p *Parser) peekNext() lexer.Token {
 	if p.pos+1 >= len(p.tokens) {
-		return lexer.Token{Type: lexer.TokEOF}
+		return lexer.Token{Kind: lexer.TokEOF}
 	}
 	return p.tokens[p.pos+1]
 }
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/lexer"
+)
+
+// syncTokens are the tokens the parser resynchronises on after a syntax
+// error: every statement keyword, plus the '}' that closes the current block.
+var syncTokens = map[lexer.Kind]bool{
+	lexer.TokParayu:         true,
+	lexer.TokKelk:           true,
+	lexer.TokAadhyamayi:     true,
+	lexer.TokEllamSheriyano: true,
+	lexer.TokOnninuMumbu:    true,
+	lexer.TokPani:           true,
+	lexer.TokThirichKodukk:  true,
+	lexer.TokNirthu:         true,
+	lexer.TokThudaru:        true,
+	lexer.TokRBrace:         true,
+	lexer.TokEOF:            true,
+}
+
+// parseStatementRecover parses one statement. If it contains a syntax error,
+// the error stays recorded, the broken statement is dropped and the parser
+// skips ahead to the next statement keyword or '}' so parsing can carry on.
+// ok is false when the statement was dropped.
+func (p *Parser) parseStatementRecover() (statement ast.ASTNode, ok bool) {
+	start := p.pos
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isBailout := r.(bailout); !isBailout {
+				panic(r)
+			}
+			p.synchronize(start)
+			statement, ok = nil, false
+		}
+	}()
+	return p.parseStatement(), true
+}
+
+// synchronize skips tokens until one that can start or end a statement. The
+// token the failed statement started on is always skipped so the parser is
+// guaranteed to make progress.
+func (p *Parser) synchronize(start int) {
+	if p.pos == start {
+		p.pos++
+	}
+	for !syncTokens[p.peek().Kind] {
+		p.pos++
+	}
+}
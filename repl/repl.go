@@ -0,0 +1,214 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Rohith04MVK/malang/ast"
+	"github.com/Rohith04MVK/malang/check"
+	"github.com/Rohith04MVK/malang/codegen"
+	"github.com/Rohith04MVK/malang/diag"
+	"github.com/Rohith04MVK/malang/interp"
+	"github.com/Rohith04MVK/malang/lexer"
+	"github.com/Rohith04MVK/malang/parser"
+)
+
+const (
+	prompt             = "malang> "
+	continuationPrompt = "...     "
+)
+
+const help = `Commands:
+  :tokens  toggle printing the tokens of each input
+  :ast     toggle printing the AST of each input
+  :go      toggle printing the Go code generated for the session so far
+  :help    show this help
+  :quit    leave the REPL
+`
+
+// REPL reads malang statements, type checks them, runs them with the
+// interpreter and prints the type of every variable they assign. Variables
+// persist between inputs.
+type REPL struct {
+	in      *bufio.Reader
+	out     io.Writer
+	checker *check.Checker
+	interp  *interp.Interpreter
+	history []ast.ASTNode // Every statement that ran, for :go
+
+	showTokens bool
+	showAST    bool
+	showGo     bool
+}
+
+// New creates a REPL reading from in and writing to out. Input read by kelk
+// comes from the same stream as the statements themselves.
+func New(in io.Reader, out io.Writer) *REPL {
+	reader := bufio.NewReader(in)
+	return &REPL{in: reader, out: out, checker: check.New(), interp: interp.New(reader, out)}
+}
+
+// Run starts the read-eval-print loop and returns when the input ends or the
+// user types :quit.
+func (r *REPL) Run() error {
+	fmt.Fprintln(r.out, "malang REPL. Type :help for commands.")
+	for {
+		source, ok := r.readInput()
+		if !ok {
+			fmt.Fprintln(r.out)
+			return nil
+		}
+		trimmed := strings.TrimSpace(source)
+		switch trimmed {
+		case "":
+			continue
+		case ":quit", ":q":
+			return nil
+		case ":help":
+			fmt.Fprint(r.out, help)
+			continue
+		case ":tokens":
+			r.showTokens = !r.showTokens
+			r.printToggle("tokens", r.showTokens)
+			continue
+		case ":ast":
+			r.showAST = !r.showAST
+			r.printToggle("ast", r.showAST)
+			continue
+		case ":go":
+			r.showGo = !r.showGo
+			r.printToggle("go", r.showGo)
+			continue
+		}
+		if strings.HasPrefix(trimmed, ":") {
+			fmt.Fprintf(r.out, "unknown command %s (type :help for a list)\n", trimmed)
+			continue
+		}
+		r.eval(source)
+	}
+}
+
+func (r *REPL) printToggle(name string, on bool) {
+	state := "off"
+	if on {
+		state = "on"
+	}
+	fmt.Fprintf(r.out, "%s: %s\n", name, state)
+}
+
+// readInput reads one complete input. Lines are accumulated while a block or
+// parenthesis or list is still open, so that an ith_sheriyano ... enkil { can be
+// typed over several lines.
+func (r *REPL) readInput() (string, bool) {
+	var source strings.Builder
+	fmt.Fprint(r.out, prompt)
+	for {
+		line, err := r.in.ReadString('\n')
+		source.WriteString(line)
+		if err != nil {
+			return source.String(), source.Len() > 0
+		}
+		if strings.HasPrefix(strings.TrimSpace(source.String()), ":") || !isIncomplete(source.String()) {
+			return source.String(), true
+		}
+		fmt.Fprint(r.out, continuationPrompt)
+	}
+}
+
+// isIncomplete reports whether source has more '{', '(' or '[' than it
+// closes, or ends inside a string or comment.
+func isIncomplete(source string) bool {
+	tokens, diags, _ := lexer.LexDiag(source)
+	for _, d := range diags {
+		if strings.HasPrefix(d.Message, "unterminated") {
+			return true
+		}
+	}
+	depth := 0
+	for _, token := range tokens {
+		switch token.Kind {
+		case lexer.TokLBrace, lexer.TokLParen, lexer.TokLBracket:
+			depth++
+		case lexer.TokRBrace, lexer.TokRParen, lexer.TokRBracket:
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+func (r *REPL) eval(source string) {
+	tokens, lexDiags, lexErr := lexer.LexDiag(source)
+	if r.showTokens {
+		fmt.Fprintln(r.out, "Tokens:", tokens)
+	}
+	program, parseDiags, parseErr := parser.NewParser(tokens).ParseDiag()
+	if lexErr != nil || parseErr != nil {
+		diags := append(lexDiags, parseDiags...)
+		diag.Sort(diags)
+		diag.Render(r.out, source, diags)
+		return
+	}
+	if r.showAST {
+		fmt.Fprintln(r.out, "AST:", program)
+	}
+	checked, diags, err := r.checker.Check(program)
+	if err != nil {
+		diag.Render(r.out, source, diags)
+		return
+	}
+
+	// A function declared in an earlier input got its signature and
+	// checked body only now, from this input's call.
+	for _, decl := range r.checker.Functions() {
+		r.interp.Declare(decl)
+	}
+	if err := r.interp.Run(checked); err != nil {
+		// The input is discarded: the interpreter has put its variables
+		// back, and the checker must forget their types too.
+		r.checker.Undo()
+		if d, ok := err.(diag.Diagnostic); ok && d.Line > 0 {
+			diag.Render(r.out, source, []diag.Diagnostic{d})
+		} else {
+			fmt.Fprintln(r.out, "error:", messageOf(err))
+		}
+		return
+	}
+	r.history = append(r.history, program.Statements...)
+	r.printAssigned(program.Statements)
+
+	if r.showGo {
+		code, _, err := codegen.GenerateCodeDiag(ast.Program{Statements: r.history})
+		if err != nil {
+			fmt.Fprintln(r.out, "error:", messageOf(err))
+			return
+		}
+		fmt.Fprint(r.out, "Generated Go Code:\n", code)
+	}
+}
+
+// printAssigned shows the type of each top-level variable assigned by statements.
+func (r *REPL) printAssigned(statements []ast.ASTNode) {
+	for _, statement := range statements {
+		var name string
+		switch s := statement.(type) {
+		case ast.AssignmentStatement:
+			name = s.Identifier
+		case ast.KelkStatement:
+			name = s.Identifier
+		default:
+			continue
+		}
+		if t, ok := r.checker.TypeOf(name); ok {
+			fmt.Fprintf(r.out, "%s: %s\n", name, t)
+		}
+	}
+}
+
+func messageOf(err error) string {
+	if d, ok := err.(diag.Diagnostic); ok {
+		return d.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,35 @@
+package repl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rohith04MVK/malang/repl"
+)
+
+// session runs the REPL over input and returns what it printed.
+func session(t *testing.T, input string) string {
+	t.Helper()
+	var out strings.Builder
+	if err := repl.New(strings.NewReader(input), &out).Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return out.String()
+}
+
+// An input that fails while running is discarded whole: a variable it
+// created before the error is gone from the checker and the interpreter
+// alike, so the next input can create it afresh with another type.
+func TestFailedInputIsDiscarded(t *testing.T) {
+	out := session(t, "a = 1.5 b = 1 / 0\na = 2\nparayu(a / 4)\nparayu(b)\n")
+	for _, want := range []string{
+		"error: integer divide by zero",
+		"malang> a: int\n",
+		"malang> 0\n",
+		"undeclared variable 'b'",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}